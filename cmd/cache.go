@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/config"
+	"github.com/maher/mvns/internal/ui"
+)
+
+// openCache opens the on-disk cache with the configured bounds. The single
+// cache.json file of older versions is dropped on the way.
+func openCache(cfg *config.Config) *api.Cache {
+	os.Remove(filepath.Join(config.ConfigDir(), "cache.json"))
+
+	var opts []api.CacheOption
+	if cfg.CacheMaxMB > 0 {
+		opts = append(opts, api.WithMaxSize(int64(cfg.CacheMaxMB)<<20))
+	}
+	for kind, v := range cfg.CacheTTL {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mvns: ignoring cache_ttl %s: %v\n", kind, err)
+			continue
+		}
+		opts = append(opts, api.WithTTL(api.Endpoint(kind), ttl))
+	}
+	return api.NewCache(filepath.Join(config.ConfigDir(), "cache"), opts...)
+}
+
+func loadCache() *api.Cache {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		def := config.Default()
+		cfg = &def
+	}
+	return openCache(cfg)
+}
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and maintain the local response cache",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stats",
+		Short: "Show the size and age of the cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache := loadCache()
+			stats, err := cache.Stats()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Location: %s\n", cache.Dir())
+			fmt.Printf("Entries:  %d (%d expired)\n", stats.Entries, stats.Expired)
+			for _, kind := range []api.Endpoint{api.EndpointSearch, api.EndpointVersions, api.EndpointFiles} {
+				fmt.Printf("  %-9s %d\n", kind+":", stats.ByKind[kind])
+			}
+			fmt.Printf("Size:     %s of %s\n", ui.FormatBytes(stats.Bytes), ui.FormatBytes(cache.MaxSize()))
+			if stats.Entries > 0 {
+				fmt.Printf("Oldest:   %s\n", stats.Oldest.Format(time.DateTime))
+				fmt.Printf("Newest:   %s\n", stats.Newest.Format(time.DateTime))
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "prune",
+		Short: "Remove expired entries and shrink the cache to its size limit",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := loadCache().Prune()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed %d entries\n", removed)
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached entry",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return loadCache().Clear()
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+	formatterPkg "github.com/maher/mvns/internal/formatter"
+)
+
+var (
+	flagCatalogFile   string
+	flagCatalogGroovy bool
+)
+
+func newCatalogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Edit a Gradle version catalog",
+	}
+	add := &cobra.Command{
+		Use:   "add <groupId:artifactId[:version]>...",
+		Short: "Add libraries to gradle/libs.versions.toml",
+		Long: "Add libraries and their versions to an existing version catalog and\n" +
+			"print the accessors to use in the build script. Libraries already in\n" +
+			"the catalog are kept; taken aliases get the group as prefix, and\n" +
+			"version refs with the same value are shared. Without a version the\n" +
+			"latest release is added.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: runCatalogAdd,
+	}
+	add.Flags().StringVarP(&flagCatalogFile, "file", "f", "gradle/libs.versions.toml", "version catalog to edit")
+	add.Flags().BoolVar(&flagCatalogGroovy, "groovy", false, "print accessors for build.gradle instead of build.gradle.kts")
+	cmd.AddCommand(add)
+	return cmd
+}
+
+func runCatalogAdd(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(flagCatalogFile)
+	if err != nil {
+		return err
+	}
+
+	deps := make([]formatterPkg.Dependency, len(args))
+	for i, arg := range args {
+		if deps[i], err = parseCoordinates(arg); err != nil {
+			return err
+		}
+	}
+
+	catalog := formatterPkg.ParseCatalog(data)
+	var (
+		client    api.Searcher
+		accessors []string
+	)
+	for _, dep := range deps {
+		if dep.Version == "" {
+			if client == nil {
+				if client, err = loadSearcher(); err != nil {
+					return err
+				}
+			}
+			if dep.Version, err = latestStableVersion(cmd.Context(), client, dep.GroupID, dep.ArtifactID); err != nil {
+				return err
+			}
+		}
+		alias, added := catalog.Add(dep)
+		if added {
+			fmt.Fprintf(os.Stderr, "added %s = %s:%s:%s\n", alias, dep.GroupID, dep.ArtifactID, dep.Version)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s:%s is already in the catalog as %s\n", dep.GroupID, dep.ArtifactID, alias)
+		}
+		accessor := formatterPkg.CatalogAccessor(alias)
+		if flagCatalogGroovy {
+			accessors = append(accessors, "implementation "+accessor)
+		} else {
+			accessors = append(accessors, "implementation("+accessor+")")
+		}
+	}
+
+	if err := os.WriteFile(flagCatalogFile, catalog.Bytes(), 0644); err != nil {
+		return err
+	}
+	for _, a := range accessors {
+		fmt.Println(a)
+	}
+	return nil
+}
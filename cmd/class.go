@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+var flagClassLimit int
+
+func newClassCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "class <class name>",
+		Short: "Find the artifacts that contain a class",
+		Long: "Find every artifact version that contains a class. A fully-qualified\n" +
+			"name such as org.slf4j.Logger matches exactly, a simple name such as\n" +
+			"Logger matches in any package.",
+		Args: cobra.ExactArgs(1),
+		RunE: runClass,
+	}
+	cmd.Flags().IntVarP(&flagClassLimit, "limit", "n", 50, "maximum number of artifact versions to list")
+	return cmd
+}
+
+func runClass(cmd *cobra.Command, args []string) error {
+	client, err := loadSearcher()
+	if err != nil {
+		return err
+	}
+	cs, ok := client.(api.ClassSearcher)
+	if !ok {
+		return fmt.Errorf("configured backends cannot search by class name")
+	}
+
+	className := strings.TrimSpace(args[0])
+	resp, err := cs.SearchClass(cmd.Context(), className, flagClassLimit, 0, false)
+	if err != nil {
+		return err
+	}
+	if len(resp.Response.Docs) == 0 {
+		fmt.Println("No artifacts found.")
+		return nil
+	}
+
+	term := className
+	if i := strings.LastIndex(term, "."); i >= 0 {
+		term = term[i+1:]
+	}
+	bold := lipgloss.NewStyle().Bold(true)
+	for _, d := range resp.Response.Docs {
+		fmt.Printf("%s:%s:%s\n", d.GroupID, d.ArtifactID, d.Version)
+		for _, c := range d.Classes {
+			if at := strings.LastIndex(c, term); at >= 0 {
+				c = c[:at] + bold.Render(term) + c[at+len(term):]
+			}
+			fmt.Printf("    %s\n", c)
+		}
+	}
+	if resp.Response.NumFound > len(resp.Response.Docs) {
+		fmt.Printf("\n%d of %d shown\n", len(resp.Response.Docs), resp.Response.NumFound)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/config"
+	"github.com/maher/mvns/internal/settings"
+)
+
+var (
+	flagFetchDir string
+	flagFetchM2  bool
+)
+
+func newFetchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fetch <groupId:artifactId[:version[:classifier]][@type]>",
+		Short: "Download an artifact file and verify its checksum",
+		Long: "Download the jar, or another file of an artifact version, and verify it\n" +
+			"against the SHA-512, SHA-256 or SHA-1 the repository publishes.\n" +
+			"Add a classifier for sources or javadoc and @pom for the POM, e.g.\n" +
+			"com.google.guava:guava:33.0.0-jre:sources or junit:junit:4.13.2@pom.\n" +
+			"Without a version the latest release is fetched.",
+		Args: cobra.ExactArgs(1),
+		RunE: runFetch,
+	}
+	cmd.Flags().StringVarP(&flagFetchDir, "dir", "d", ".", "directory to download into")
+	cmd.Flags().BoolVar(&flagFetchM2, "m2", false, "download into the local Maven repository layout (~/.m2/repository)")
+	return cmd
+}
+
+func runFetch(cmd *cobra.Command, args []string) error {
+	f, err := parseArtifact(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := loadSearcher()
+	if err != nil {
+		return err
+	}
+	d, ok := client.(api.Downloader)
+	if !ok {
+		return fmt.Errorf("configured backends cannot download files")
+	}
+
+	ctx := cmd.Context()
+	if f.Version == "" {
+		f.Version, err = latestStableVersion(ctx, client, f.GroupID, f.ArtifactID)
+		if err != nil {
+			return err
+		}
+	}
+
+	dir := flagFetchDir
+	if flagFetchM2 {
+		repo, err := localRepository()
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(repo, filepath.FromSlash(path.Dir(f.Path())))
+	}
+
+	file, alg, err := api.DownloadFile(ctx, d, f, dir, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "verified %s\n", alg)
+	fmt.Println(file)
+	return nil
+}
+
+// parseArtifact accepts "g:a[:v[:classifier]][@type]".
+func parseArtifact(s string) (api.ArtifactFile, error) {
+	var f api.ArtifactFile
+	coords := strings.TrimSpace(s)
+	if i := strings.LastIndex(coords, "@"); i >= 0 {
+		f.Extension = coords[i+1:]
+		coords = coords[:i]
+		if f.Extension == "" {
+			return f, fmt.Errorf("invalid coordinates %q: missing type after @", s)
+		}
+	}
+	parts := strings.Split(coords, ":")
+	if len(parts) < 2 || len(parts) > 4 || slices.Contains(parts, "") {
+		return f, fmt.Errorf("invalid coordinates %q (want groupId:artifactId[:version[:classifier]][@type])", s)
+	}
+	f.GroupID, f.ArtifactID = parts[0], parts[1]
+	if len(parts) > 2 {
+		f.Version = parts[2]
+	}
+	if len(parts) > 3 {
+		f.Classifier = parts[3]
+	}
+	return f, nil
+}
+
+// localRepository is the local Maven repository settings.xml points at.
+func localRepository() (string, error) {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		def := config.Default()
+		cfg = &def
+	}
+	file := cfg.Settings
+	if file == "" {
+		file = settings.DefaultPath()
+	}
+	s, err := settings.Load(file)
+	if err != nil {
+		return "", fmt.Errorf("settings.xml: %w", err)
+	}
+	if repo := s.LocalRepositoryPath(); repo != "" {
+		return repo, nil
+	}
+	return "", fmt.Errorf("cannot locate the local Maven repository")
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+	formatterPkg "github.com/maher/mvns/internal/formatter"
+)
+
+// identifyWorkers bounds how many jars are hashed and looked up at once.
+const identifyWorkers = 8
+
+var flagIdentifyFormat string
+
+func newIdentifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identify <file.jar>...",
+		Short: "Map jar files back to their coordinates by SHA-1",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runIdentify,
+	}
+	cmd.Flags().StringVar(&flagIdentifyFormat, "format", "", "print identified jars as dependency snippets ("+formatterPkg.Keys()+")")
+	return cmd
+}
+
+// identified is the outcome of looking up one file. Doc is nil when no
+// repository knows the checksum.
+type identified struct {
+	Path string
+	SHA1 string
+	Doc  *api.Doc
+	// Also lists further coordinates the same file was published under.
+	Also []api.Doc
+	Err  error
+}
+
+func identifyFiles(ctx context.Context, cs api.ChecksumSearcher, paths []string) []identified {
+	results := make([]identified, len(paths))
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, identifyWorkers)
+	)
+	for i, p := range paths {
+		results[i].Path = p
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *identified) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.SHA1, r.Err = api.FileSHA1(r.Path)
+			if r.Err != nil {
+				return
+			}
+			resp, err := cs.SearchChecksum(ctx, r.SHA1, false)
+			if err != nil {
+				r.Err = err
+				return
+			}
+			if docs := resp.Response.Docs; len(docs) > 0 {
+				r.Doc = &docs[0]
+				r.Also = docs[1:]
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func runIdentify(cmd *cobra.Command, args []string) error {
+	var f formatterPkg.Formatter
+	if flagIdentifyFormat != "" {
+		var err error
+		if f, err = lookupFormat(flagIdentifyFormat); err != nil {
+			return err
+		}
+	}
+
+	client, err := loadSearcher()
+	if err != nil {
+		return err
+	}
+	cs, ok := client.(api.ChecksumSearcher)
+	if !ok {
+		return fmt.Errorf("configured backends cannot search by checksum")
+	}
+
+	var failed, unknown int
+	for _, r := range identifyFiles(cmd.Context(), cs, args) {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", r.Path, r.Err)
+		case r.Doc == nil:
+			unknown++
+			// Keep stdout pasteable when printing snippets
+			if f != nil {
+				fmt.Fprintf(os.Stderr, "%s: unknown (sha1 %s)\n", r.Path, r.SHA1)
+			} else {
+				fmt.Printf("%-40s unknown (sha1 %s)\n", r.Path, r.SHA1)
+			}
+		case f != nil:
+			fmt.Println(f.Format(formatterPkg.Dependency{
+				GroupID:    r.Doc.GroupID,
+				ArtifactID: r.Doc.ArtifactID,
+				Version:    r.Doc.Version,
+			}))
+		default:
+			fmt.Printf("%-40s %s\n", r.Path, coordinates(*r.Doc))
+			for _, d := range r.Also {
+				fmt.Printf("%-40s   also %s\n", "", coordinates(d))
+			}
+		}
+	}
+	// Let scripts tell a complete answer from a partial one
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d of %d lookups failed", failed, len(args))
+	case unknown == len(args):
+		return fmt.Errorf("no jar could be identified")
+	}
+	return nil
+}
+
+func coordinates(d api.Doc) string {
+	return d.GroupID + ":" + d.ArtifactID + ":" + d.Version
+}
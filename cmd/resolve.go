@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/maher/mvns/internal/api"
+	formatterPkg "github.com/maher/mvns/internal/formatter"
+	"github.com/maher/mvns/internal/version"
+)
+
+// splitConstraint parses "g:a@constraint". ok is false for anything else,
+// such as free text that happens to contain an @, which is searched for.
+func splitConstraint(query string) (dep formatterPkg.Dependency, c version.Constraint, ok bool) {
+	coords, raw, found := strings.Cut(query, "@")
+	if !found || strings.ContainsAny(strings.TrimSpace(coords), " \t") {
+		return dep, c, false
+	}
+	dep, err := parseCoordinates(coords)
+	if err != nil || dep.Version != "" {
+		return dep, c, false
+	}
+	c, err = version.ParseConstraint(strings.TrimSpace(raw))
+	if err != nil {
+		return dep, c, false
+	}
+	return dep, c, true
+}
+
+// resolveVersion picks the version a constraint resolves to today.
+func resolveVersion(ctx context.Context, client api.Searcher, groupID, artifactID string, c version.Constraint) (string, error) {
+	resp, err := client.Versions(ctx, groupID, artifactID, 0, false)
+	if err != nil {
+		return "", err
+	}
+	versions := make([]string, len(resp.Response.Docs))
+	for i, d := range resp.Response.Docs {
+		versions[i] = d.Version
+	}
+	v, ok := c.Resolve(versions)
+	if !ok {
+		return "", fmt.Errorf("no version of %s:%s matches %s", groupID, artifactID, c)
+	}
+	return v, nil
+}
+
+// runResolve answers "--query g:a@constraint" with the concrete version, as
+// coordinates or as a snippet in format.
+func runResolve(ctx context.Context, client api.Searcher, query, format string) error {
+	dep, c, ok := splitConstraint(query)
+	if !ok {
+		return fmt.Errorf("invalid query %q (want groupId:artifactId@constraint)", query)
+	}
+
+	var (
+		f   formatterPkg.Formatter
+		err error
+	)
+	if format != "" {
+		if f, err = lookupFormat(format); err != nil {
+			return err
+		}
+	}
+
+	dep.Version, err = resolveVersion(ctx, client, dep.GroupID, dep.ArtifactID, c)
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		fmt.Println(f.Format(dep))
+		return nil
+	}
+	fmt.Printf("%s:%s:%s\n", dep.GroupID, dep.ArtifactID, dep.Version)
+	return nil
+}
@@ -1,11 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
-	"strings"
 	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,17 +15,24 @@ import (
 	formatterPkg "github.com/maher/mvns/internal/formatter"
 	"github.com/maher/mvns/internal/history"
 	"github.com/maher/mvns/internal/i18n"
+	"github.com/maher/mvns/internal/ranking"
 	"github.com/maher/mvns/internal/ui"
 	"github.com/maher/mvns/locales"
 )
 
 var (
-	flagLang       string
-	flagTheme      string
-	flagQuery      string
-	flagFormat     string
-	flagClearCache bool
-	Version        = "dev"
+	flagLang        string
+	flagTheme       string
+	flagQuery       string
+	flagFormat      string
+	flagClearCache  bool
+	flagLive        bool
+	flagOffline     bool
+	flagRank        string
+	flagExplain     bool
+	flagDownloadDir string
+	flagDownloadM2  bool
+	Version         = "dev"
 )
 
 func NewRootCmd() *cobra.Command {
@@ -40,26 +46,35 @@ func NewRootCmd() *cobra.Command {
 	cmd.Flags().StringVar(&flagLang, "lang", "", "language (en, de)")
 	cmd.Flags().StringVar(&flagTheme, "theme", "", "theme (dark, light)")
 	cmd.Flags().StringVar(&flagQuery, "query", "", "non-interactive search query")
-	cmd.Flags().StringVar(&flagFormat, "format", "", "output format for non-interactive mode (maven, gradle, gradle-kts)")
+	cmd.Flags().StringVar(&flagFormat, "format", "", "output format for non-interactive mode ("+formatterPkg.Keys()+")")
 	cmd.Flags().BoolVar(&flagClearCache, "clear-cache", false, "clear the local results cache")
+	cmd.Flags().MarkDeprecated("clear-cache", `use "mvns cache clear" instead`)
+	cmd.Flags().BoolVar(&flagLive, "live", false, "search while typing")
+	cmd.Flags().StringVar(&flagRank, "rank", "", "result ordering ("+ranking.Keys()+")")
+	cmd.Flags().BoolVar(&flagExplain, "explain", false, "show how non-interactive results were scored")
+	cmd.Flags().StringVar(&flagDownloadDir, "download-dir", ".", "directory the snippet screen downloads into")
+	cmd.Flags().BoolVar(&flagDownloadM2, "m2", false, "download from the snippet screen into the local Maven repository layout (~/.m2/repository)")
+	cmd.PersistentFlags().BoolVar(&flagOffline, "offline", false, "answer only from the local cache")
+
+	cmd.AddCommand(newTreeCmd())
+	cmd.AddCommand(newCacheCmd())
+	cmd.AddCommand(newClassCmd())
+	cmd.AddCommand(newIdentifyCmd())
+	cmd.AddCommand(newScanCmd())
+	cmd.AddCommand(newFetchCmd())
+	cmd.AddCommand(newCatalogCmd())
 
 	return cmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
 	var (
-		cfg   *config.Config
-		hist  *history.History
-		cache *api.Cache
-		wg    sync.WaitGroup
+		cfg  *config.Config
+		hist *history.History
+		wg   sync.WaitGroup
 	)
 
-	cachePath := filepath.Join(config.ConfigDir(), "cache.json")
-	if flagClearCache {
-		os.Remove(cachePath)
-	}
-
-	wg.Add(3)
+	wg.Add(2)
 	go func() {
 		defer wg.Done()
 		var err error
@@ -80,13 +95,13 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	go func() {
-		defer wg.Done()
-		cache = api.NewCache(cachePath)
-	}()
-
 	wg.Wait()
 
+	cache := openCache(cfg)
+	if flagClearCache {
+		cache.Clear()
+	}
+
 	lang := cfg.Lang
 	if flagLang != "" {
 		lang = flagLang
@@ -101,16 +116,50 @@ func run(cmd *cobra.Command, args []string) error {
 		locale, _ = i18n.NewFromFS(locales.FS, ".", "en")
 	}
 
-	client := api.NewClient(api.WithCache(cache))
+	rankKey := cfg.Rank
+	if flagRank != "" {
+		rankKey = flagRank
+	}
+	strategy := ranking.Relevance
+	if rankKey != "" {
+		var ok bool
+		if strategy, ok = ranking.Lookup(rankKey); !ok {
+			return fmt.Errorf("unknown ranking: %s (use %s)", rankKey, ranking.Keys())
+		}
+	}
+
+	client, err := newSearcher(cfg, cache)
+	if err != nil {
+		return err
+	}
+
+	// A version constraint is resolved rather than searched for
+	if _, _, ok := splitConstraint(flagQuery); ok {
+		return runResolve(cmd.Context(), client, flagQuery, flagFormat)
+	}
 
 	// If query is provided with a format, it's strictly non-interactive
-	if flagQuery != "" && flagFormat != "" {
-		return runNonInteractive(client, locale, flagQuery, flagFormat)
+	if flagQuery != "" && (flagFormat != "" || flagExplain) {
+		return runNonInteractive(cmd.Context(), client, locale, strategy, flagQuery, flagFormat)
 	}
 
+	// The TUI shows expired results at once and refreshes them in place
+	cache.SetServeStale(true)
+
 	theme := ui.NewTheme(themeName)
 
 	app := ui.NewApp(client, locale, theme, hist)
+	app.SetLiveSearch(cfg.LiveSearch || flagLive)
+	app.SetOffline(cache.Offline())
+	app.SetRanking(strategy)
+	app.SetDownloadDir(flagDownloadDir)
+	if flagDownloadM2 {
+		repo, err := localRepository()
+		if err != nil {
+			return err
+		}
+		app.SetDownloadRepository(repo)
+	}
 
 	// If query is provided without format, pre-fill and trigger search in TUI
 	var p *tea.Program
@@ -125,10 +174,29 @@ func run(cmd *cobra.Command, args []string) error {
 	return err
 }
 
-func runNonInteractive(client *api.Client, locale *i18n.Locale, query, format string) error {
-	resp, err := client.SearchMultimodal(query, 10, 0, false)
+// loadSearcher sets up the configured backends for subcommands, which do not
+// need the locale and history the TUI loads.
+func loadSearcher() (api.Searcher, error) {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		def := config.Default()
+		cfg = &def
+	}
+	return newSearcher(cfg, openCache(cfg))
+}
+
+func lookupFormat(format string) (formatterPkg.Formatter, error) {
+	f, ok := formatterPkg.Lookup(format)
+	if !ok {
+		return nil, fmt.Errorf("unknown format: %s (use %s)", format, formatterPkg.Keys())
+	}
+	return f, nil
+}
+
+func runNonInteractive(ctx context.Context, client api.Searcher, locale *i18n.Locale, strategy ranking.Strategy, query, format string) error {
+	resp, err := client.SearchMultimodal(ctx, query, 10, 0, false)
 	if err != nil {
-		return fmt.Errorf("%s: %w", locale.T("error.network"), err)
+		return fmt.Errorf("%s: %w", ui.ErrorMessage(locale, err), err)
 	}
 
 	if len(resp.Response.Docs) == 0 {
@@ -136,12 +204,15 @@ func runNonInteractive(client *api.Client, locale *i18n.Locale, query, format st
 		return nil
 	}
 
-	// Deduplicate and sort
-	docs := sortMultimodalResults(resp.Response.Docs, query)
+	ranked := strategy.Rank(dedupe(resp.Response.Docs), query)
 
 	if format != "" {
-		doc := docs[0]
-		
+		doc := ranked[0].Doc
+		if flagExplain {
+			// Keep stdout pasteable
+			fmt.Fprintf(os.Stderr, "%s:%s  %.1f  %s\n", doc.GroupID, doc.ArtifactID, ranked[0].Score, ranked[0].Explain())
+		}
+
 		version := doc.LatestVersion
 		if version == "" {
 			version = doc.Version
@@ -149,14 +220,8 @@ func runNonInteractive(client *api.Client, locale *i18n.Locale, query, format st
 
 		// If the latest version is a pre-release, try to find the latest stable one
 		if doc.IsPreRelease() {
-			vResp, err := client.Versions(doc.GroupID, doc.ArtifactID, 100, false)
-			if err == nil && len(vResp.Response.Docs) > 0 {
-				for _, vDoc := range vResp.Response.Docs {
-					if !vDoc.IsPreRelease() {
-						version = vDoc.Version
-						break
-					}
-				}
+			if v, err := latestStableVersion(ctx, client, doc.GroupID, doc.ArtifactID); err == nil {
+				version = v
 			}
 		}
 
@@ -167,26 +232,20 @@ func runNonInteractive(client *api.Client, locale *i18n.Locale, query, format st
 			Scope:      doc.DetectScope(),
 		}
 
-		var f formatterPkg.Formatter
-		switch format {
-		case "maven":
-			f = &formatterPkg.Maven{}
-		case "gradle":
-			f = &formatterPkg.GradleGroovy{}
-		case "gradle-kts":
-			f = &formatterPkg.GradleKotlin{}
-		default:
-			return fmt.Errorf("unknown format: %s (use maven, gradle, gradle-kts)", format)
+		f, err := lookupFormat(format)
+		if err != nil {
+			return err
 		}
 
 		fmt.Println(f.Format(dep))
 		return nil
 	}
 
-	for i, doc := range docs {
+	for i, r := range ranked {
 		if i >= 10 {
 			break
 		}
+		doc := r.Doc
 		v := doc.LatestVersion
 		if v == "" {
 			v = doc.Version
@@ -197,12 +256,15 @@ func runNonInteractive(client *api.Client, locale *i18n.Locale, query, format st
 			doc.Time().Format("2006-01-02"),
 			doc.Packaging,
 		)
+		if flagExplain {
+			fmt.Printf("    %.1f  %s\n", r.Score, r.Explain())
+		}
 	}
 	return nil
 }
 
-func sortMultimodalResults(results []api.Doc, query string) []api.Doc {
-	// Deduplicate by ID
+// dedupe drops repeated docs, keeping the first.
+func dedupe(results []api.Doc) []api.Doc {
 	seen := make(map[string]bool)
 	unique := make([]api.Doc, 0, len(results))
 	for _, doc := range results {
@@ -211,29 +273,5 @@ func sortMultimodalResults(results []api.Doc, query string) []api.Doc {
 			unique = append(unique, doc)
 		}
 	}
-	
-	// Sort
-	lowerQuery := strings.ToLower(query)
-	sort.Slice(unique, func(i, j int) bool {
-		iExact := unique[i].ArtifactID == query
-		jExact := unique[j].ArtifactID == query
-		if iExact != jExact {
-			return iExact
-		}
-
-		iNorm := strings.ReplaceAll(strings.ToLower(unique[i].GroupID), ".", "-")
-		jNorm := strings.ReplaceAll(strings.ToLower(unique[j].GroupID), ".", "-")
-		iGroup := strings.Contains(iNorm, lowerQuery)
-		jGroup := strings.Contains(jNorm, lowerQuery)
-		if iGroup != jGroup {
-			return iGroup
-		}
-
-		if unique[i].VersionCount != unique[j].VersionCount {
-			return unique[i].VersionCount > unique[j].VersionCount
-		}
-		return unique[i].Timestamp > unique[j].Timestamp
-	})
-	
 	return unique
 }
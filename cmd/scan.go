@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+	formatterPkg "github.com/maher/mvns/internal/formatter"
+	"github.com/maher/mvns/internal/resolver"
+)
+
+var flagScanFormat string
+
+func newScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan <dir>",
+		Short: "Turn a directory of jars into a dependencies block",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runScan,
+	}
+	cmd.Flags().StringVar(&flagScanFormat, "format", "maven", "output format ("+formatterPkg.Keys()+")")
+	return cmd
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	f, err := lookupFormat(flagScanFormat)
+	if err != nil {
+		return err
+	}
+
+	jars, err := findJars(args[0])
+	if err != nil {
+		return err
+	}
+	if len(jars) == 0 {
+		return fmt.Errorf("no jars found in %s", args[0])
+	}
+
+	client, err := loadSearcher()
+	if err != nil {
+		return err
+	}
+	cs, ok := client.(api.ChecksumSearcher)
+	if !ok {
+		return fmt.Errorf("configured backends cannot search by checksum")
+	}
+
+	ctx := cmd.Context()
+	var (
+		deps    []formatterPkg.Dependency
+		paths   = make(map[string]string)
+		unknown []identified
+		failed  []identified
+	)
+	for _, r := range identifyFiles(ctx, cs, jars) {
+		switch {
+		case r.Err != nil:
+			failed = append(failed, r)
+		case r.Doc == nil:
+			unknown = append(unknown, r)
+		default:
+			key := r.Doc.GroupID + ":" + r.Doc.ArtifactID
+			if first, ok := paths[key]; ok {
+				fmt.Fprintf(os.Stderr, "%s: duplicate of %s (%s)\n", r.Path, first, key)
+				continue
+			}
+			paths[key] = r.Path
+			deps = append(deps, formatterPkg.Dependency{
+				GroupID:    r.Doc.GroupID,
+				ArtifactID: r.Doc.ArtifactID,
+				Version:    r.Doc.Version,
+				Scope:      r.Doc.DetectScope(),
+			})
+		}
+	}
+
+	roots := deps
+	var covered []resolver.Covered
+	if fetcher, ok := client.(api.POMFetcher); ok {
+		roots, covered, err = resolver.New(fetcher).Roots(ctx, deps)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, "configured backends cannot fetch POMs; transitive jars are kept")
+	}
+
+	if len(roots) > 0 {
+		fmt.Println(formatterPkg.Block(f, roots))
+	}
+
+	for _, c := range covered {
+		d := c.Dependency
+		if c.Pinned {
+			fmt.Fprintf(os.Stderr, "%s: kept to pin %s, %s:%s resolves %s\n",
+				paths[d.GroupID+":"+d.ArtifactID], d.Version, c.By.GroupID, c.By.ArtifactID, c.Version)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s: transitive via %s:%s\n",
+			paths[d.GroupID+":"+d.ArtifactID], c.By.GroupID, c.By.ArtifactID)
+	}
+	for _, r := range unknown {
+		fmt.Fprintf(os.Stderr, "%s: unknown (sha1 %s)\n", r.Path, r.SHA1)
+	}
+	for _, r := range failed {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", r.Path, r.Err)
+	}
+	return nil
+}
+
+// findJars lists the jars below dir, skipping source and javadoc jars.
+func findJars(dir string) ([]string, error) {
+	var jars []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := strings.ToLower(d.Name())
+		if d.IsDir() || !strings.HasSuffix(name, ".jar") {
+			return nil
+		}
+		if strings.HasSuffix(name, "-sources.jar") || strings.HasSuffix(name, "-javadoc.jar") {
+			return nil
+		}
+		jars = append(jars, p)
+		return nil
+	})
+	return jars, err
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/config"
+	"github.com/maher/mvns/internal/settings"
+)
+
+// centralRepositoryURL is the address Maven itself uses for the central
+// repository; mirrorOf rules are matched against it.
+const centralRepositoryURL = "https://repo.maven.apache.org/maven2"
+
+func newSearcher(cfg *config.Config, cache *api.Cache) (api.Searcher, error) {
+	path := cfg.Settings
+	if path == "" {
+		path = settings.DefaultPath()
+	}
+	s, err := settings.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("settings.xml: %w", err)
+	}
+	cache.SetOffline(cfg.Offline || flagOffline)
+
+	// Every backend takes its requests from one limiter, so the bound
+	// holds for mvns as a whole
+	opts := []api.Option{
+		api.WithCache(cache),
+		api.WithRetries(cfg.Retries),
+		api.WithLimiter(api.NewLimiter(cfg.Concurrency)),
+		api.WithProxy(s.ProxyFunc()),
+	}
+	if cfg.CABundle != "" {
+		pool, err := loadCABundle(cfg.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, api.WithRootCAs(pool))
+	}
+
+	if len(cfg.Backends) == 0 {
+		return defaultSearcher(s, opts), nil
+	}
+
+	searchers := make([]api.Searcher, 0, len(cfg.Backends))
+	for _, b := range cfg.Backends {
+		backendOpts := opts
+		if b.Type == "" || b.Type == api.BackendCentral {
+			backendOpts = append(backendOpts, repositoryOptions(s, settings.CentralID, centralRepositoryURL)...)
+		} else if srv := s.Server(b.Name); srv != nil {
+			backendOpts = append(backendOpts, api.WithCredentials(b.URL, credentials(srv)))
+		}
+		searcher, err := api.NewBackend(b.Type, b.URL, b.Repository, backendOpts...)
+		if err != nil {
+			name := b.Name
+			if name == "" {
+				name = b.Type
+			}
+			return nil, fmt.Errorf("backend %s: %w", name, err)
+		}
+		searchers = append(searchers, searcher)
+	}
+	return api.NewMulti(searchers...), nil
+}
+
+// defaultSearcher searches Maven Central, downloading POMs through its
+// mirror when one is configured, and adds the repositories of active
+// settings.xml profiles.
+func defaultSearcher(s *settings.Settings, opts []api.Option) api.Searcher {
+	central := api.NewClient(append(opts, repositoryOptions(s, settings.CentralID, centralRepositoryURL)...)...)
+
+	searchers := []api.Searcher{central}
+	seen := map[string]bool{resolveRepository(s, settings.CentralID, centralRepositoryURL): true}
+	for _, r := range s.Repositories() {
+		u := resolveRepository(s, r.ID, r.URL)
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		searchers = append(searchers, api.NewMetadata(u, append(opts, repositoryOptions(s, r.ID, r.URL)...)...))
+	}
+	return api.NewMulti(searchers...)
+}
+
+// resolveRepository returns the URL a repository is actually reached at.
+func resolveRepository(s *settings.Settings, id, url string) string {
+	if m := s.Mirror(id, url); m != nil {
+		return m.URL
+	}
+	return url
+}
+
+// repositoryOptions points the client at the repository's mirror, if any,
+// and attaches the matching server credentials.
+func repositoryOptions(s *settings.Settings, id, url string) []api.Option {
+	var opts []api.Option
+	if m := s.Mirror(id, url); m != nil {
+		id, url = m.ID, m.URL
+		opts = append(opts, api.WithRepositoryURL(url))
+	}
+	if srv := s.Server(id); srv != nil {
+		opts = append(opts, api.WithCredentials(url, credentials(srv)))
+	}
+	return opts
+}
+
+func credentials(srv *settings.Server) api.Credentials {
+	creds := api.Credentials{Headers: srv.Headers()}
+	if srv.Encrypted() {
+		fmt.Fprintf(os.Stderr, "mvns: ignoring encrypted password of server %q\n", srv.ID)
+		return creds
+	}
+	if srv.Username != "" {
+		creds.Username, creds.Password = srv.Username, srv.Password
+	} else {
+		creds.Token = srv.Password
+	}
+	return creds
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ca bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("ca bundle %s: no certificates found", path)
+	}
+	return pool, nil
+}
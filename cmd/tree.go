@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maher/mvns/internal/api"
+	formatterPkg "github.com/maher/mvns/internal/formatter"
+	"github.com/maher/mvns/internal/resolver"
+	"github.com/maher/mvns/internal/version"
+)
+
+var flagVerbose bool
+
+func newTreeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree <groupId:artifactId[:version]>",
+		Short: "Print the transitive dependency tree of an artifact",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTree,
+	}
+	cmd.Flags().BoolVarP(&flagVerbose, "verbose", "v", false, "also show dependencies omitted by conflict mediation")
+	return cmd
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	client, err := loadSearcher()
+	if err != nil {
+		return err
+	}
+	fetcher, ok := client.(api.POMFetcher)
+	if !ok {
+		return fmt.Errorf("configured backends cannot fetch POMs")
+	}
+
+	dep, err := parseCoordinates(args[0])
+	if err != nil {
+		return err
+	}
+	if dep.Version == "" {
+		dep.Version, err = latestStableVersion(cmd.Context(), client, dep.GroupID, dep.ArtifactID)
+		if err != nil {
+			return err
+		}
+	}
+
+	root, err := resolver.New(fetcher).Resolve(cmd.Context(), dep)
+	if err != nil {
+		return err
+	}
+	resolver.Print(os.Stdout, root, flagVerbose)
+	fmt.Printf("\n%d dependencies\n", root.Count())
+	return nil
+}
+
+// parseCoordinates accepts "g:a" or "g:a:v".
+func parseCoordinates(s string) (formatterPkg.Dependency, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return formatterPkg.Dependency{}, fmt.Errorf("invalid coordinates %q (want groupId:artifactId[:version])", s)
+	}
+	dep := formatterPkg.Dependency{GroupID: parts[0], ArtifactID: parts[1]}
+	if len(parts) == 3 {
+		dep.Version = parts[2]
+	}
+	return dep, nil
+}
+
+func latestStableVersion(ctx context.Context, client api.Searcher, groupID, artifactID string) (string, error) {
+	resp, err := client.Versions(ctx, groupID, artifactID, 100, false)
+	if err != nil {
+		return "", err
+	}
+	docs := resp.Response.Docs
+	if len(docs) == 0 {
+		return "", fmt.Errorf("no versions found for %s:%s", groupID, artifactID)
+	}
+	// The highest version, not the newest upload: backports of an older
+	// line are often published after the latest release
+	latest := ""
+	for _, d := range docs {
+		if !d.IsPreRelease() && (latest == "" || version.Compare(d.Version, latest) > 0) {
+			latest = d.Version
+		}
+	}
+	if latest == "" {
+		return docs[0].Version, nil
+	}
+	return latest, nil
+}
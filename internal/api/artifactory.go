@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Artifactory searches a JFrog Artifactory instance using the GAVC and quick
+// artifact search endpoints.
+type Artifactory struct {
+	client     *Client
+	repository string
+}
+
+func NewArtifactory(baseURL, repository string, opts ...Option) *Artifactory {
+	opts = append(opts,
+		WithBaseURL(strings.TrimRight(baseURL, "/")),
+		WithHeader("X-Result-Detail", "info"),
+	)
+	return &Artifactory{client: NewClient(opts...), repository: repository}
+}
+
+type artifactoryResponse struct {
+	Results []struct {
+		URI          string `json:"uri"`
+		Path         string `json:"path"`
+		LastModified string `json:"lastModified"`
+	} `json:"results"`
+}
+
+func decodeArtifactory(r io.Reader) (*SearchResponse, error) {
+	var raw artifactoryResponse
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var (
+		docs  []Doc
+		index = make(map[string]int)
+	)
+	for _, res := range raw.Results {
+		p := res.Path
+		if p == "" {
+			p = storagePath(res.URI)
+		}
+		doc, ext, ok := parseRepositoryPath(p)
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, res.LastModified); err == nil {
+			doc.Timestamp = t.UnixMilli()
+		}
+
+		if i, seen := index[doc.ID]; seen {
+			if ext != "pom" {
+				docs[i].Packaging = ext
+			}
+			if doc.Timestamp > docs[i].Timestamp {
+				docs[i].Timestamp = doc.Timestamp
+			}
+			continue
+		}
+		doc.Packaging = ext
+		index[doc.ID] = len(docs)
+		docs = append(docs, doc)
+	}
+	sortByTimestamp(docs)
+
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(docs),
+			Docs:     docs,
+		},
+	}, nil
+}
+
+// storagePath strips the "/api/storage/<repo>" prefix from a result URI.
+func storagePath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	_, rest, ok := strings.Cut(u.Path, "/api/storage/")
+	if !ok {
+		return ""
+	}
+	_, p, _ := strings.Cut(rest, "/")
+	return "/" + p
+}
+
+// parseRepositoryPath maps a Maven2-layout file path such as
+// "/org/acme/lib/1.0/lib-1.0.jar" to its coordinates and file extension.
+func parseRepositoryPath(p string) (Doc, string, bool) {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if len(parts) < 4 {
+		return Doc{}, "", false
+	}
+	n := len(parts)
+	file, version, artifact := parts[n-1], parts[n-2], parts[n-3]
+	prefix := artifact + "-" + version
+	if !strings.HasPrefix(file, prefix+".") {
+		// Classified files like lib-1.0-sources.jar and metadata are skipped
+		return Doc{}, "", false
+	}
+	ext := strings.TrimPrefix(path.Ext(file), ".")
+	if isChecksumExt(ext) {
+		return Doc{}, "", false
+	}
+	group := strings.Join(parts[:n-3], ".")
+	return Doc{
+		ID:         group + ":" + artifact + ":" + version,
+		GroupID:    group,
+		ArtifactID: artifact,
+		Version:    version,
+	}, ext, true
+}
+
+func (a *Artifactory) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("name", "*"+query+"*.pom")
+	return a.search(ctx, "/api/search/artifact", params, nil, rows, start, bypassCache)
+}
+
+func (a *Artifactory) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return &SearchResponse{}, nil
+	}
+
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if !q.Keyword() {
+		params := url.Values{}
+		if q.Group != "" {
+			params.Set("g", q.Group)
+		}
+		if q.Artifact != "" {
+			params.Set("a", q.Artifact)
+		}
+		return a.search(ctx, "/api/search/gavc", params, q, rows, start, bypassCache)
+	}
+
+	params := url.Values{}
+	params.Set("name", "*"+strings.Join(q.Terms, "*")+"*.pom")
+	return a.search(ctx, "/api/search/artifact", params, q, rows, start, bypassCache)
+}
+
+// search keeps the artifacts matching filter, which may be nil.
+func (a *Artifactory) search(ctx context.Context, endpoint string, params url.Values, filter *Query, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	resp, err := a.fetch(ctx, endpoint, params, EndpointSearch, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := filter.Filter(aggregate(resp.Response.Docs))
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(artifacts),
+			Start:    start,
+			Docs:     paginate(artifacts, rows, start),
+		},
+		CacheInfo: resp.CacheInfo,
+	}, nil
+}
+
+func (a *Artifactory) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("g", groupID)
+	params.Set("a", artifactID)
+	resp, err := a.fetch(ctx, "/api/search/gavc", params, EndpointVersions, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	out := *resp
+	out.Response.Docs = paginate(resp.Response.Docs, rows, 0)
+	return &out, nil
+}
+
+func (a *Artifactory) fetch(ctx context.Context, endpoint string, params url.Values, kind Endpoint, bypassCache bool) (*SearchResponse, error) {
+	if a.repository != "" {
+		params.Set("repos", a.repository)
+	}
+	return a.client.get(ctx, a.client.baseURL+endpoint+"?"+params.Encode(), kind, bypassCache, decodeArtifactory)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestArtifactoryVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search/gavc" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/api/search/gavc")
+		}
+		if r.Header.Get("X-Result-Detail") != "info" {
+			t.Errorf("X-Result-Detail = %q, want %q", r.Header.Get("X-Result-Detail"), "info")
+		}
+		if r.URL.Query().Get("repos") != "libs-release" {
+			t.Errorf("repos = %q, want %q", r.URL.Query().Get("repos"), "libs-release")
+		}
+		w.Write([]byte(`{"results": [
+			{"path":"/org/acme/lib/1.0/lib-1.0.pom","lastModified":"2020-01-01T00:00:00.000Z"},
+			{"path":"/org/acme/lib/1.0/lib-1.0.jar","lastModified":"2020-01-01T00:00:00.000Z"},
+			{"path":"/org/acme/lib/1.0/lib-1.0-sources.jar","lastModified":"2020-01-01T00:00:00.000Z"},
+			{"uri":"http://repo/api/storage/libs-release/org/acme/lib/2.0/lib-2.0.jar","lastModified":"2022-01-01T00:00:00.000Z"}
+		]}`))
+	}))
+	defer server.Close()
+
+	a := NewArtifactory(server.URL, "libs-release")
+	resp, err := a.Versions(context.Background(), "org.acme", "lib", 20, false)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	docs := resp.Response.Docs
+	if len(docs) != 2 {
+		t.Fatalf("docs len = %d, want 2", len(docs))
+	}
+	if docs[0].Version != "2.0" || docs[0].GroupID != "org.acme" {
+		t.Errorf("docs[0] = %+v, want org.acme:lib:2.0", docs[0])
+	}
+	if docs[1].Packaging != "jar" {
+		t.Errorf("packaging = %q, want %q", docs[1].Packaging, "jar")
+	}
+}
+
+func TestParseRepositoryPath(t *testing.T) {
+	tests := []struct {
+		path string
+		id   string
+		ok   bool
+	}{
+		{"/com/google/inject/guice/7.0.0/guice-7.0.0.jar", "com.google.inject:guice:7.0.0", true},
+		{"/com/google/inject/guice/7.0.0/guice-7.0.0.jar.sha1", "", false},
+		{"/com/google/inject/guice/maven-metadata.xml", "", false},
+		{"guice-7.0.0.jar", "", false},
+	}
+
+	for _, tt := range tests {
+		doc, _, ok := parseRepositoryPath(tt.path)
+		if ok != tt.ok || doc.ID != tt.id {
+			t.Errorf("parseRepositoryPath(%q) = %q, %v, want %q, %v", tt.path, doc.ID, ok, tt.id, tt.ok)
+		}
+	}
+}
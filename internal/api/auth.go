@@ -0,0 +1,66 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Credentials authenticate requests to one repository. A username with a
+// password is sent as basic auth, a lone token as a bearer header.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+	Headers  map[string]string
+}
+
+type credentialScope struct {
+	prefix string
+	creds  Credentials
+}
+
+// WithCredentials authenticates every request whose URL starts with prefix,
+// so a client that also talks to a public search endpoint never leaks them.
+func WithCredentials(prefix string, creds Credentials) Option {
+	return func(c *Client) {
+		c.credentials = append(c.credentials, credentialScope{prefix: strings.TrimRight(prefix, "/"), creds: creds})
+	}
+}
+
+// WithProxy routes requests through the proxy chosen by fn, which has the
+// same contract as http.Transport.Proxy.
+func WithProxy(fn func(*http.Request) (*url.URL, error)) Option {
+	return func(c *Client) { c.transport.Proxy = fn }
+}
+
+// WithRootCAs replaces the system roots used to verify TLS servers.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		if c.transport.TLSClientConfig == nil {
+			c.transport.TLSClientConfig = &tls.Config{}
+		}
+		c.transport.TLSClientConfig.RootCAs = pool
+	}
+}
+
+func (c *Client) authenticate(req *http.Request) {
+	reqURL := req.URL.String()
+	for _, s := range c.credentials {
+		if reqURL != s.prefix && !strings.HasPrefix(reqURL, s.prefix+"/") && !strings.HasPrefix(reqURL, s.prefix+"?") {
+			continue
+		}
+		switch {
+		case s.creds.Username != "":
+			req.SetBasicAuth(s.creds.Username, s.creds.Password)
+		case s.creds.Token != "":
+			req.Header.Set("Authorization", "Bearer "+s.creds.Token)
+		}
+		for k, v := range s.creds.Headers {
+			req.Header.Set(k, v)
+		}
+		return
+	}
+}
@@ -1,78 +1,445 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// Endpoint classifies cached requests so each kind gets its own TTL.
+type Endpoint string
+
+const (
+	// EndpointSearch covers keyword and coordinate searches.
+	EndpointSearch Endpoint = "search"
+	// EndpointVersions covers version listings, which grow over time.
+	EndpointVersions Endpoint = "versions"
+	// EndpointFiles covers published repository files such as POMs, which
+	// never change.
+	EndpointFiles Endpoint = "files"
+)
+
+const defaultCacheSize = 64 << 20
+
+var defaultTTLs = map[Endpoint]time.Duration{
+	EndpointSearch:   time.Hour,
+	EndpointVersions: 6 * time.Hour,
+	EndpointFiles:    90 * 24 * time.Hour,
+}
+
 type CacheEntry struct {
-	Response  *SearchResponse `json:"response"`
+	Key       string          `json:"key"`
+	Endpoint  Endpoint        `json:"endpoint"`
+	Response  *SearchResponse `json:"response,omitempty"`
+	Body      []byte          `json:"body,omitempty"`
 	Timestamp time.Time       `json:"timestamp"`
 }
 
+// Cache stores one file per entry below dir. Files are replaced atomically,
+// so a crash never leaves a half-written entry and concurrent mvns processes
+// only ever see complete ones. Writers share a lock file that pruning and
+// clearing take exclusively. Reads bump an entry's mtime, which is what the
+// size bound evicts by.
 type Cache struct {
-	path    string
-	entries map[string]CacheEntry
-	mu      sync.RWMutex
+	dir      string
+	maxBytes int64
+	ttls     map[Endpoint]time.Duration
+
+	mu         sync.Mutex
+	offline    bool
+	serveStale bool
+	// written counts bytes stored since the size bound was last enforced
+	written int64
+}
+
+type CacheOption func(*Cache)
+
+// WithMaxSize bounds the total size of the cache in bytes.
+func WithMaxSize(bytes int64) CacheOption {
+	return func(c *Cache) {
+		if bytes > 0 {
+			c.maxBytes = bytes
+		}
+	}
+}
+
+func WithTTL(e Endpoint, ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		if ttl > 0 {
+			c.ttls[e] = ttl
+		}
+	}
 }
 
-func NewCache(path string) *Cache {
+func NewCache(dir string, opts ...CacheOption) *Cache {
 	c := &Cache{
-		path:    path,
-		entries: make(map[string]CacheEntry),
+		dir:      dir,
+		maxBytes: defaultCacheSize,
+		ttls:     make(map[Endpoint]time.Duration, len(defaultTTLs)),
+	}
+	for e, ttl := range defaultTTLs {
+		c.ttls[e] = ttl
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
-	c.load()
 	return c
 }
 
-func (c *Cache) Get(key string, ttl time.Duration) (*SearchResponse, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache) Dir() string {
+	return c.dir
+}
 
-	entry, ok := c.entries[key]
-	if !ok {
+func (c *Cache) MaxSize() int64 {
+	return c.maxBytes
+}
+
+// SetOffline makes the cache the only source of answers: entries are served
+// regardless of their TTL and clients never touch the network.
+func (c *Cache) SetOffline(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offline = on
+}
+
+func (c *Cache) Offline() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offline
+}
+
+// SetServeStale makes Get return expired responses flagged as stale instead
+// of missing, for callers that show them at once and revalidate with
+// bypassCache in the background.
+func (c *Cache) SetServeStale(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serveStale = on
+}
+
+func (c *Cache) modes() (offline, serveStale bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offline, c.serveStale
+}
+
+func (c *Cache) Get(key string, e Endpoint) (*SearchResponse, bool) {
+	entry, ok := c.read(key)
+	if !ok || entry.Response == nil {
 		return nil, false
 	}
 
-	if time.Since(entry.Timestamp) > ttl {
+	age := time.Since(entry.Timestamp)
+	stale := age > c.ttls[e]
+	offline, serveStale := c.modes()
+	if offline || (stale && serveStale) {
+		out := *entry.Response
+		out.CacheInfo = CacheInfo{Stale: stale, Age: age}
+		return &out, true
+	}
+	if stale {
 		return nil, false
 	}
 
 	return entry.Response, true
 }
 
-func (c *Cache) Set(key string, resp *SearchResponse) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *Cache) Set(key string, e Endpoint, resp *SearchResponse) {
+	c.write(CacheEntry{Key: key, Endpoint: e, Response: resp, Timestamp: time.Now()})
+}
+
+// GetBody is Get for raw files. Offline, expired bodies are returned and
+// flagged stale the same way.
+func (c *Cache) GetBody(key string, e Endpoint) ([]byte, CacheInfo, bool) {
+	entry, ok := c.read(key)
+	if !ok || entry.Body == nil {
+		return nil, CacheInfo{}, false
+	}
 
-	c.entries[key] = CacheEntry{
-		Response:  resp,
-		Timestamp: time.Now(),
+	age := time.Since(entry.Timestamp)
+	stale := age > c.ttls[e]
+	if c.Offline() {
+		return entry.Body, CacheInfo{Stale: stale, Age: age}, true
+	}
+	if stale {
+		return nil, CacheInfo{}, false
 	}
-	c.save()
+
+	return entry.Body, CacheInfo{}, true
+}
+
+func (c *Cache) SetBody(key string, e Endpoint, body []byte) {
+	c.write(CacheEntry{Key: key, Endpoint: e, Body: body, Timestamp: time.Now()})
 }
 
 func (c *Cache) Clear() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	return c.withLock(true, func() error {
+		entries, err := os.ReadDir(c.dir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				if err := os.RemoveAll(filepath.Join(c.dir, e.Name())); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+type CacheStats struct {
+	Entries int
+	Bytes   int64
+	// Expired entries are past their TTL and only served offline.
+	Expired int
+	Oldest  time.Time
+	Newest  time.Time
+	ByKind  map[Endpoint]int
+}
+
+func (c *Cache) Stats() (CacheStats, error) {
+	stats := CacheStats{ByKind: make(map[Endpoint]int)}
+	files, err := c.files()
+	if err != nil {
+		return stats, err
+	}
+	for _, f := range files {
+		entry, ok := c.readFile(f.path)
+		if !ok {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += f.size
+		stats.ByKind[entry.Endpoint]++
+		if c.expired(entry) {
+			stats.Expired++
+		}
+		if stats.Oldest.IsZero() || entry.Timestamp.Before(stats.Oldest) {
+			stats.Oldest = entry.Timestamp
+		}
+		if entry.Timestamp.After(stats.Newest) {
+			stats.Newest = entry.Timestamp
+		}
+	}
+	return stats, nil
+}
+
+// Prune removes expired and unreadable entries, then evicts the least
+// recently used ones until the cache fits its size bound. It returns the
+// number of files removed.
+func (c *Cache) Prune() (int, error) {
+	removed := 0
+	err := c.withLock(true, func() error {
+		files, err := c.files()
+		if err != nil {
+			return err
+		}
+		var kept []cacheFile
+		for _, f := range files {
+			if entry, ok := c.readFile(f.path); ok && !c.expired(entry) {
+				kept = append(kept, f)
+				continue
+			}
+			if os.Remove(f.path) == nil {
+				removed++
+			}
+		}
+		n, err := c.evict(kept)
+		removed += n
+		return err
+	})
+	return removed, err
+}
+
+func (c *Cache) expired(entry CacheEntry) bool {
+	ttl, ok := c.ttls[entry.Endpoint]
+	if !ok {
+		ttl = c.ttls[EndpointSearch]
+	}
+	return time.Since(entry.Timestamp) > ttl
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, name[:2], name+".json")
+}
+
+func (c *Cache) read(key string) (CacheEntry, bool) {
+	p := c.path(key)
+	entry, ok := c.readFile(p)
+	if !ok || entry.Key != key {
+		return CacheEntry{}, false
+	}
+	now := time.Now()
+	os.Chtimes(p, now, now)
+	return entry, true
+}
 
-	c.entries = make(map[string]CacheEntry)
-	return os.Remove(c.path)
+func (c *Cache) readFile(p string) (CacheEntry, bool) {
+	var entry CacheEntry
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
 }
 
-func (c *Cache) load() {
-	data, err := os.ReadFile(c.path)
+func (c *Cache) write(entry CacheEntry) {
+	data, err := json.Marshal(entry)
 	if err != nil {
 		return
 	}
-	json.Unmarshal(data, &c.entries)
+	p := c.path(entry.Key)
+	err = c.withLock(false, func() error {
+		return writeAtomic(p, data)
+	})
+	if err != nil {
+		return
+	}
+
+	// Enforcing the bound scans the whole directory, so only do it once a
+	// tenth of the budget has been written since the last time
+	c.mu.Lock()
+	c.written += int64(len(data))
+	enforce := c.written > c.maxBytes/10
+	if enforce {
+		c.written = 0
+	}
+	c.mu.Unlock()
+	if enforce {
+		c.withLock(true, func() error {
+			files, err := c.files()
+			if err != nil {
+				return err
+			}
+			_, err = c.evict(files)
+			return err
+		})
+	}
+}
+
+func writeAtomic(p string, data []byte) error {
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
 }
 
-func (c *Cache) save() {
-	os.MkdirAll(filepath.Dir(c.path), 0755)
-	data, _ := json.Marshal(c.entries)
-	os.WriteFile(c.path, data, 0644)
+// files lists the entry files. Temporary files left behind by a crash are
+// removed once they are clearly abandoned.
+func (c *Cache) files() ([]cacheFile, error) {
+	var files []cacheFile
+	err := filepath.WalkDir(c.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		switch {
+		case strings.HasPrefix(d.Name(), ".tmp-"):
+			if time.Since(info.ModTime()) > time.Hour {
+				os.Remove(p)
+			}
+		case strings.HasSuffix(d.Name(), ".json"):
+			files = append(files, cacheFile{path: p, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	return files, err
+}
+
+// evict removes the least recently used files until the rest fit maxBytes.
+func (c *Cache) evict(files []cacheFile) (int, error) {
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if total <= c.maxBytes {
+		return 0, nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	removed := 0
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, err
+		}
+		total -= f.size
+		removed++
+	}
+	return removed, nil
+}
+
+// withLock runs fn while holding the cache's lock file, shared or exclusive.
+func (c *Cache) withLock(exclusive bool, fn func() error) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(c.dir, ".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := lockFile(f, exclusive); err != nil {
+		return err
+	}
+	defer unlockFile(f)
+	return fn()
 }
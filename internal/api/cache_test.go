@@ -0,0 +1,99 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	NewCache(dir).Set("k", EndpointSearch, &SearchResponse{Response: ResponseBody{NumFound: 3}})
+
+	// A second instance, as another process would, sees the entry
+	resp, ok := NewCache(dir).Get("k", EndpointSearch)
+	if !ok || resp.Response.NumFound != 3 {
+		t.Fatalf("Get = %+v, %v; want the stored response", resp, ok)
+	}
+	if _, ok := NewCache(dir, WithTTL(EndpointSearch, time.Nanosecond)).Get("k", EndpointSearch); ok {
+		t.Error("expired entry should miss")
+	}
+}
+
+func TestCacheIgnoresCorruptEntries(t *testing.T) {
+	c := NewCache(t.TempDir())
+	c.SetBody("k", EndpointFiles, []byte("pom"))
+	if err := os.WriteFile(c.path("k"), []byte(`{"key":"k","bo`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := c.GetBody("k", EndpointFiles); ok {
+		t.Error("truncated entry should miss")
+	}
+	if n, err := c.Prune(); err != nil || n != 1 {
+		t.Errorf("Prune = %d, %v; want the corrupt entry removed", n, err)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	body := []byte(strings.Repeat("x", 1000))
+	c := NewCache(t.TempDir(), WithMaxSize(1<<20))
+	for _, k := range []string{"a", "b", "c"} {
+		c.SetBody(k, EndpointFiles, body)
+	}
+	// Make "a" the most recently used, "b" the least
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(c.path("b"), old, old)
+	os.Chtimes(c.path("c"), old.Add(time.Minute), old.Add(time.Minute))
+	c.GetBody("a", EndpointFiles)
+
+	// One byte over budget, so exactly one entry must go
+	files, _ := c.files()
+	c.maxBytes = -1
+	for _, f := range files {
+		c.maxBytes += f.size
+	}
+	if n, err := c.Prune(); err != nil || n != 1 {
+		t.Fatalf("Prune = %d, %v; want one eviction", n, err)
+	}
+	for k, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, _, ok := c.GetBody(k, EndpointFiles); ok != want {
+			t.Errorf("entry %s present = %v, want %v", k, ok, want)
+		}
+	}
+}
+
+func TestCacheStatsAndClear(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir)
+	c.Set("s", EndpointSearch, &SearchResponse{})
+	c.SetBody("f", EndpointFiles, []byte("pom"))
+	os.WriteFile(filepath.Join(dir, ".tmp-stray"), nil, 0644)
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.Entries != 2 || stats.ByKind[EndpointFiles] != 1 || stats.Bytes == 0 {
+		t.Errorf("Stats = %+v, want two entries", stats)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if stats, _ := c.Stats(); stats.Entries != 0 {
+		t.Errorf("Entries after Clear = %d, want 0", stats.Entries)
+	}
+}
+
+func TestCacheServeStale(t *testing.T) {
+	c := NewCache(t.TempDir(), WithTTL(EndpointVersions, time.Nanosecond))
+	c.Set("k", EndpointVersions, &SearchResponse{})
+	c.SetServeStale(true)
+
+	resp, ok := c.Get("k", EndpointVersions)
+	if !ok || !resp.Stale {
+		t.Errorf("Get = %+v, %v; want a stale hit", resp, ok)
+	}
+}
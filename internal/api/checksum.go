@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ChecksumSearcher is implemented by backends that can look up published
+// files by SHA-1. The returned docs are the artifact versions the file was
+// published as; usually exactly one.
+type ChecksumSearcher interface {
+	SearchChecksum(ctx context.Context, sha1 string, bypassCache bool) (*SearchResponse, error)
+}
+
+var (
+	_ ChecksumSearcher = (*Client)(nil)
+	_ ChecksumSearcher = (*Nexus)(nil)
+	_ ChecksumSearcher = (*Artifactory)(nil)
+	_ ChecksumSearcher = (*Multi)(nil)
+)
+
+// FileSHA1 returns the hex SHA-1 of the file at path.
+func FileSHA1(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *Client) SearchChecksum(ctx context.Context, sha1 string, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", fmt.Sprintf(`1:"%s"`, strings.ToLower(sha1)))
+	params.Set("rows", "20")
+	params.Set("wt", "json")
+	// The mapping from a published file to its coordinates never changes
+	return c.doRequest(ctx, params, EndpointFiles, bypassCache)
+}
+
+func (n *Nexus) SearchChecksum(ctx context.Context, sha1 string, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("sha1", strings.ToLower(sha1))
+	docs, _, info, err := n.components(ctx, params, 0, EndpointFiles, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResponse{
+		Response:  ResponseBody{NumFound: len(docs), Docs: docs},
+		CacheInfo: info,
+	}, nil
+}
+
+func (a *Artifactory) SearchChecksum(ctx context.Context, sha1 string, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("sha1", strings.ToLower(sha1))
+	return a.fetch(ctx, "/api/search/checksum", params, EndpointFiles, bypassCache)
+}
+
+func (m *Multi) SearchChecksum(ctx context.Context, sha1 string, bypassCache bool) (*SearchResponse, error) {
+	return m.fanOut(ctx, func(s Searcher) (*SearchResponse, error) {
+		cs, ok := s.(ChecksumSearcher)
+		if !ok {
+			return nil, ErrUnsupported
+		}
+		return cs.SearchChecksum(ctx, sha1, bypassCache)
+	})
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSHA1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.jar")
+	if err := os.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := FileSHA1(path)
+	if err != nil {
+		t.Fatalf("FileSHA1 failed: %v", err)
+	}
+	if want := "a9993e364706816aba3e25717850c26c9cd0d89d"; sum != want {
+		t.Errorf("FileSHA1 = %s, want %s", sum, want)
+	}
+}
+
+func TestClientSearchChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("q"); q != `1:"a9993e364706816aba3e25717850c26c9cd0d89d"` {
+			t.Errorf("q = %s", q)
+		}
+		w.Write([]byte(`{"response":{"numFound":1,"docs":[{"id":"g:a:1","g":"g","a":"a","v":"1","p":"jar"}]}}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(WithBaseURL(server.URL)).SearchChecksum(context.Background(), "A9993E364706816ABA3E25717850C26C9CD0D89D", false)
+	if err != nil {
+		t.Fatalf("SearchChecksum failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 1 || resp.Response.Docs[0].Version != "1" {
+		t.Errorf("docs = %+v, want g:a:1", resp.Response.Docs)
+	}
+}
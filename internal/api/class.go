@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ClassPrefix marks a search-box query as a class-name search.
+const ClassPrefix = "class:"
+
+// ClassSearcher is implemented by backends that index the classes inside
+// jars. Results hold one doc per artifact version, with Classes listing the
+// matching class names.
+type ClassSearcher interface {
+	SearchClass(ctx context.Context, className string, rows, start int, bypassCache bool) (*SearchResponse, error)
+}
+
+var (
+	_ ClassSearcher = (*Client)(nil)
+	_ ClassSearcher = (*Artifactory)(nil)
+	_ ClassSearcher = (*Multi)(nil)
+)
+
+// ParseClassQuery reports whether input asks for a class search and returns
+// the class name.
+func ParseClassQuery(input string) (string, bool) {
+	input = strings.TrimSpace(input)
+	if len(input) < len(ClassPrefix) || !strings.EqualFold(input[:len(ClassPrefix)], ClassPrefix) {
+		return "", false
+	}
+	name := strings.TrimSpace(input[len(ClassPrefix):])
+	return name, name != ""
+}
+
+// BuildClassQuery searches fully-qualified names with fc: and simple names
+// with c:.
+func BuildClassQuery(className string) string {
+	if strings.Contains(className, ".") {
+		return "fc:" + solrQuote(className)
+	}
+	return "c:" + solrQuote(className)
+}
+
+func (c *Client) SearchClass(ctx context.Context, className string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	field := "ch"
+	if strings.Contains(className, ".") {
+		field = "fch"
+	}
+	params := url.Values{}
+	params.Set("q", BuildClassQuery(className))
+	params.Set("core", "gav")
+	params.Set("rows", fmt.Sprintf("%d", rows))
+	params.Set("start", fmt.Sprintf("%d", start))
+	params.Set("wt", "json")
+	params.Set("hl", "true")
+	params.Set("hl.fl", field)
+
+	resp, err := c.doRequest(ctx, params, EndpointSearch, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(className, ".") {
+		return resp, nil
+	}
+	// An exact fc: match is the class itself, highlighted or not
+	out := *resp
+	out.Response.Docs = make([]Doc, len(resp.Response.Docs))
+	for i, d := range resp.Response.Docs {
+		if len(d.Classes) == 0 {
+			d.Classes = []string{className}
+		}
+		out.Response.Docs[i] = d
+	}
+	return &out, nil
+}
+
+// stripHighlight removes the <em> markup Solr wraps around matches.
+func stripHighlight(s string) string {
+	return strings.NewReplacer("<em>", "", "</em>", "").Replace(s)
+}
+
+type artifactoryArchiveResponse struct {
+	Results []struct {
+		Entry       string   `json:"entry"`
+		ArchiveURIs []string `json:"archiveUris"`
+	} `json:"results"`
+}
+
+func decodeArtifactoryArchive(r io.Reader) (*SearchResponse, error) {
+	var raw artifactoryArchiveResponse
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var (
+		docs  []Doc
+		index = make(map[string]int)
+	)
+	for _, res := range raw.Results {
+		class := strings.ReplaceAll(strings.TrimSuffix(res.Entry, ".class"), "/", ".")
+		for _, uri := range res.ArchiveURIs {
+			doc, ext, ok := parseRepositoryPath(storagePath(uri))
+			if !ok {
+				continue
+			}
+			if i, seen := index[doc.ID]; seen {
+				docs[i].Classes = append(docs[i].Classes, class)
+				continue
+			}
+			doc.Packaging = ext
+			doc.Classes = []string{class}
+			index[doc.ID] = len(docs)
+			docs = append(docs, doc)
+		}
+	}
+
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(docs),
+			Docs:     docs,
+		},
+	}, nil
+}
+
+// SearchClass uses Artifactory's archive entry search, which matches class
+// file names; package qualifiers are checked afterwards.
+func (a *Artifactory) SearchClass(ctx context.Context, className string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	simple := className
+	if i := strings.LastIndex(className, "."); i >= 0 {
+		simple = className[i+1:]
+	}
+	params := url.Values{}
+	params.Set("name", simple+".class")
+	if a.repository != "" {
+		params.Set("repos", a.repository)
+	}
+	resp, err := a.client.get(ctx, a.client.baseURL+"/api/search/archive?"+params.Encode(), EndpointSearch, bypassCache, decodeArtifactoryArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []Doc
+	for _, d := range resp.Response.Docs {
+		var classes []string
+		for _, c := range d.Classes {
+			if classMatches(c, className) {
+				classes = append(classes, c)
+			}
+		}
+		if len(classes) > 0 {
+			d.Classes = classes
+			docs = append(docs, d)
+		}
+	}
+	sortByTimestamp(docs)
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(docs),
+			Start:    start,
+			Docs:     paginate(docs, rows, start),
+		},
+		CacheInfo: resp.CacheInfo,
+	}, nil
+}
+
+// classMatches compares a fully-qualified class with a query that may be
+// a simple name.
+func classMatches(class, query string) bool {
+	if strings.Contains(query, ".") {
+		return class == query
+	}
+	return class == query || strings.HasSuffix(class, "."+query)
+}
+
+func (m *Multi) SearchClass(ctx context.Context, className string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	return m.fanOut(ctx, func(s Searcher) (*SearchResponse, error) {
+		cs, ok := s.(ClassSearcher)
+		if !ok {
+			return nil, ErrUnsupported
+		}
+		return cs.SearchClass(ctx, className, rows, start, bypassCache)
+	})
+}
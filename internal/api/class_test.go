@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseClassQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"class:org.slf4j.Logger", "org.slf4j.Logger", true},
+		{"  Class: Logger ", "Logger", true},
+		{"class:", "", false},
+		{"org.slf4j:slf4j-api", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseClassQuery(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseClassQuery(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if q := BuildClassQuery("org.slf4j.Logger"); q != `fc:"org.slf4j.Logger"` {
+		t.Errorf("BuildClassQuery(fqcn) = %q", q)
+	}
+	if q := BuildClassQuery("Logger"); q != `c:"Logger"` {
+		t.Errorf("BuildClassQuery(simple) = %q", q)
+	}
+	if q := BuildClassQuery(`Log"ger\`); q != `c:"Log\"ger\\"` {
+		t.Errorf("BuildClassQuery(quotes) = %q", q)
+	}
+}
+
+func TestClientSearchClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("q") != `c:"Logger"` || q.Get("hl.fl") != "ch" || q.Get("core") != "gav" {
+			t.Errorf("query = %v", q)
+		}
+		w.Write([]byte(`{
+			"response": {"numFound": 1, "docs": [{"id":"org.slf4j:slf4j-api:2.0.9","g":"org.slf4j","a":"slf4j-api","v":"2.0.9"}]},
+			"highlighting": {"org.slf4j:slf4j-api:2.0.9": {"ch": ["org.slf4j.<em>Logger</em>"]}}
+		}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(WithBaseURL(server.URL)).SearchClass(context.Background(), "Logger", 20, 0, false)
+	if err != nil {
+		t.Fatalf("SearchClass failed: %v", err)
+	}
+	if got := resp.Response.Docs[0].Classes; !reflect.DeepEqual(got, []string{"org.slf4j.Logger"}) {
+		t.Errorf("Classes = %q, want the highlighted class without markup", got)
+	}
+}
+
+func TestArtifactorySearchClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search/archive" || r.URL.Query().Get("name") != "Logger.class" {
+			t.Errorf("request = %s", r.URL)
+		}
+		base := "http://" + r.Host + "/api/storage/libs/"
+		w.Write([]byte(`{"results": [
+			{"entry": "org/slf4j/Logger.class", "archiveUris": ["` + base + `org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar"]},
+			{"entry": "org/other/Logger.class", "archiveUris": ["` + base + `org/other/other/1/other-1.jar"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewArtifactory(server.URL, "libs").SearchClass(context.Background(), "org.slf4j.Logger", 20, 0, false)
+	if err != nil {
+		t.Fatalf("SearchClass failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 1 || resp.Response.Docs[0].ID != "org.slf4j:slf4j-api:2.0.9" {
+		t.Errorf("docs = %+v, want only the slf4j jar", resp.Response.Docs)
+	}
+}
@@ -1,21 +1,38 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
 )
 
 const defaultBaseURL = "https://search.maven.org/solrsearch/select"
 
+// Client talks to a Solr endpoint with the search.maven.org query syntax. It
+// is the default Searcher and also provides the HTTP and cache plumbing the
+// other backends reuse.
 type Client struct {
 	baseURL    string
+	repoURL    string
 	httpClient *http.Client
-	cache      *Cache
+	// downloads streams artifact files, which may take longer than the
+	// httpClient timeout allows
+	downloads *http.Client
+	transport *http.Transport
+	cache     *Cache
+	header    http.Header
+
+	credentials []credentialScope
+
+	retries   int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	slots     Limiter
 }
 
 type Option func(*Client)
@@ -24,14 +41,34 @@ func WithBaseURL(u string) Option {
 	return func(c *Client) { c.baseURL = u }
 }
 
+// WithRepositoryURL sets the Maven2-layout repository that POMs and other
+// artifact files are downloaded from.
+func WithRepositoryURL(u string) Option {
+	return func(c *Client) { c.repoURL = strings.TrimRight(u, "/") }
+}
+
 func WithCache(cache *Cache) Option {
 	return func(c *Client) { c.cache = cache }
 }
 
+// WithHeader adds a header to every request sent by the client.
+func WithHeader(key, value string) Option {
+	return func(c *Client) { c.header.Set(key, value) }
+}
+
 func NewClient(opts ...Option) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	c := &Client{
 		baseURL:    defaultBaseURL,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		repoURL:    defaultRepositoryURL,
+		httpClient: &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		downloads:  &http.Client{Transport: transport},
+		transport:  transport,
+		header:     make(http.Header),
+		retries:    defaultRetries,
+		baseDelay:  defaultBaseDelay,
+		maxDelay:   defaultMaxDelay,
+		slots:      NewLimiter(defaultConcurrency),
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -39,7 +76,7 @@ func NewClient(opts ...Option) *Client {
 	return c
 }
 
-func (c *Client) Search(query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+func (c *Client) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
 	params := url.Values{}
 	params.Set("q", query)
 	params.Set("rows", fmt.Sprintf("%d", rows))
@@ -47,114 +84,240 @@ func (c *Client) Search(query string, rows, start int, bypassCache bool) (*Searc
 	params.Set("wt", "json")
 	params.Set("fl", "id,g,a,v,latestVersion,p,timestamp,versionCount")
 
-	return c.doRequest(params, bypassCache)
+	return c.doRequest(ctx, params, EndpointSearch, bypassCache)
 }
 
-func (c *Client) SearchMultimodal(query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+func (c *Client) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return &SearchResponse{}, nil
 	}
 
-	var queries []string
-	if strings.Contains(query, ":") || (strings.Contains(query, ".") && !strings.Contains(query, " ")) {
-		// It looks like a GAV or Group search, use the smart builder
-		queries = []string{BuildQuery(query)}
-	} else {
-		// It's a keyword search, use multimodal strategy
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []string{q.Solr()}
+	if q.Keyword() {
+		// A keyword may name an artifact or group exactly, so also try those
+		kw := solrQuote(q.Text())
 		queries = []string{
-			fmt.Sprintf(`a:"%s"`, query), // Exact Artifact
-			fmt.Sprintf(`g:"%s"`, query), // Exact Group
-			query,                        // General keyword
+			q.with("a:" + kw), // Exact Artifact
+			q.with("g:" + kw), // Exact Group
+			q.Solr(),          // General keyword
 		}
 	}
 
-	var (
-		wg      sync.WaitGroup
-		mu      sync.Mutex
-		results []Doc
-		total   int
-		errs    []error
-	)
-
-	wg.Add(len(queries))
-	for _, q := range queries {
-		go func(q string) {
-			defer wg.Done()
-			resp, err := c.Search(q, rows, start, bypassCache)
-			mu.Lock()
-			defer mu.Unlock()
-			if err != nil {
-				errs = append(errs, err)
-				return
-			}
-			results = append(results, resp.Response.Docs...)
-			if resp.Response.NumFound > total {
-				total = resp.Response.NumFound
-			}
-		}(q)
+	streams := make([]*stream, len(queries))
+	for i, sub := range queries {
+		streams[i] = &stream{
+			chunk:  rows,
+			filter: q,
+			fetch: func(start int) (*SearchResponse, error) {
+				return c.Search(ctx, sub, rows, start, bypassCache)
+			},
+		}
 	}
-	wg.Wait()
+	return merge(ctx, streams, rows, start)
+}
 
-	if len(results) == 0 && len(errs) > 0 {
-		return nil, errs[0]
+// solrMaxRows is the most rows search.maven.org returns per request.
+const solrMaxRows = 200
+
+// Versions lists versions newest first. rows <= 0 lists all of them, paging
+// past the server's row limit.
+func (c *Client) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+	if rows > 0 && rows <= solrMaxRows {
+		return c.versions(ctx, groupID, artifactID, rows, 0, bypassCache)
 	}
 
-	return &SearchResponse{
-		Response: ResponseBody{
-			NumFound: total,
-			Docs:     results,
-		},
-	}, nil
+	var out SearchResponse
+	for start := 0; ; start += solrMaxRows {
+		n := solrMaxRows
+		if rows > 0 && rows-start < n {
+			n = rows - start
+		}
+		resp, err := c.versions(ctx, groupID, artifactID, n, start, bypassCache)
+		if err != nil {
+			return nil, err
+		}
+		out.add(resp.CacheInfo)
+		out.Response.NumFound = resp.Response.NumFound
+		out.Response.Docs = append(out.Response.Docs, resp.Response.Docs...)
+
+		got := len(out.Response.Docs)
+		if len(resp.Response.Docs) < n || got >= resp.Response.NumFound || (rows > 0 && got >= rows) {
+			return &out, nil
+		}
+	}
 }
 
-func (c *Client) Versions(groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+func (c *Client) versions(ctx context.Context, groupID, artifactID string, rows, start int, bypassCache bool) (*SearchResponse, error) {
 	params := url.Values{}
 	params.Set("q", fmt.Sprintf(`g:"%s" AND a:"%s"`, groupID, artifactID))
 	params.Set("rows", fmt.Sprintf("%d", rows))
+	if start > 0 {
+		// Left out otherwise, so the first page shares its cache entry
+		// with a plain Versions call
+		params.Set("start", fmt.Sprintf("%d", start))
+	}
 	params.Set("core", "gav")
 	params.Set("sort", "timestamp desc")
 	params.Set("wt", "json")
 	params.Set("fl", "id,g,a,v,latestVersion,p,timestamp,versionCount")
 
-	return c.doRequest(params, bypassCache)
+	return c.doRequest(ctx, params, EndpointVersions, bypassCache)
 }
 
-func (c *Client) doRequest(params url.Values, bypassCache bool) (*SearchResponse, error) {
-	reqURL := c.baseURL + "?" + params.Encode()
+func (c *Client) doRequest(ctx context.Context, params url.Values, kind Endpoint, bypassCache bool) (*SearchResponse, error) {
+	return c.get(ctx, c.baseURL+"?"+params.Encode(), kind, bypassCache, decodeSolr)
+}
 
+// get fetches reqURL through the response cache and decodes the body with
+// decode. Every backend shares it so caching and headers behave the same.
+func (c *Client) get(ctx context.Context, reqURL string, kind Endpoint, bypassCache bool, decode func(io.Reader) (*SearchResponse, error)) (*SearchResponse, error) {
 	// Check cache
-	if c.cache != nil && !bypassCache {
-		if val, ok := c.cache.Get(reqURL, 24*time.Hour); ok {
+	if c.cache != nil && (!bypassCache || c.offline(reqURL)) {
+		if val, ok := c.cache.Get(reqURL, kind); ok {
 			return val, nil
 		}
 	}
+	if c.offline(reqURL) {
+		return nil, ErrNotCached
+	}
+
+	body, err := c.open(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	result, err := decode(body)
 	if err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	// Save to cache
+	if c.cache != nil {
+		c.cache.Set(reqURL, kind, result)
+	}
+
+	return result, nil
+}
+
+// getBody is get for raw files such as POMs. info tells whether the body
+// came from the cache past its TTL.
+func (c *Client) getBody(ctx context.Context, reqURL string, kind Endpoint, bypassCache bool) ([]byte, CacheInfo, error) {
+	if c.cache != nil && (!bypassCache || c.offline(reqURL)) {
+		if val, info, ok := c.cache.GetBody(reqURL, kind); ok {
+			return val, info, nil
+		}
+	}
+	if c.offline(reqURL) {
+		return nil, CacheInfo{}, ErrNotCached
+	}
+
+	body, err := c.open(ctx, reqURL)
+	if err != nil {
+		return nil, CacheInfo{}, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, CacheInfo{}, fmt.Errorf("read failed: %w", err)
+	}
+
+	if c.cache != nil {
+		c.cache.SetBody(reqURL, kind, data)
+	}
+
+	return data, CacheInfo{}, nil
+}
+
+// offline reports whether reqURL must be answered from the cache. Local
+// file:// repositories stay readable offline.
+func (c *Client) offline(reqURL string) bool {
+	return c.cache != nil && c.cache.Offline() && !strings.HasPrefix(reqURL, "file:")
+}
+
+// open performs a GET and returns the body of a 200 response. Transient
+// failures are retried with backoff; anything else comes back as a
+// StatusError or a wrapped transport error.
+func (c *Client) open(ctx context.Context, reqURL string) (io.ReadCloser, error) {
+	return c.openWith(ctx, c.httpClient, reqURL)
+}
+
+// openWith is open through hc, so downloads can use a client without the
+// overall request timeout.
+func (c *Client) openWith(ctx context.Context, hc *http.Client, reqURL string) (io.ReadCloser, error) {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var body io.ReadCloser
+		body, err = c.try(ctx, hc, reqURL)
+		if err == nil {
+			return body, nil
+		}
+		if attempt >= c.retries || !retryable(ctx, err) || RetryAfter(err) > maxRetryAfter {
+			return nil, err
+		}
+		if serr := sleep(ctx, c.backoff(attempt, err)); serr != nil {
+			return nil, serr
+		}
+	}
+}
+
+func (c *Client) try(ctx context.Context, hc *http.Client, reqURL string) (io.ReadCloser, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		release()
 		return nil, fmt.Errorf("create request failed: %w", err)
 	}
 	req.Header.Set("User-Agent", "mvns/1.0 (https://github.com/maher90-90/mvns)")
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	c.authenticate(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
+		release()
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		resp.Body.Close()
+		release()
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
-	var result SearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode failed: %w", err)
-	}
+	return releasingBody{ReadCloser: resp.Body, release: release, size: resp.ContentLength}, nil
+}
 
-	// Save to cache
-	if c.cache != nil {
-		c.cache.Set(reqURL, &result)
+func decodeSolr(r io.Reader) (*SearchResponse, error) {
+	var result struct {
+		SearchResponse
+		// Class searches highlight the matching class names per doc ID
+		Highlighting map[string]map[string][]string `json:"highlighting"`
 	}
-
-	return &result, nil
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, err
+	}
+	for i, doc := range result.Response.Docs {
+		for _, hits := range result.Highlighting[doc.ID] {
+			for _, hit := range hits {
+				result.Response.Docs[i].Classes = append(result.Response.Docs[i].Classes, stripHighlight(hit))
+			}
+		}
+	}
+	return &result.SearchResponse, nil
 }
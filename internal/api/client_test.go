@@ -1,9 +1,21 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestClientSearch(t *testing.T) {
@@ -26,7 +38,7 @@ func TestClientSearch(t *testing.T) {
 	defer server.Close()
 
 	c := NewClient(WithBaseURL(server.URL))
-	resp, err := c.Search("guice", 20, 0)
+	resp, err := c.Search(context.Background(), "guice", 20, 0, false)
 	if err != nil {
 		t.Fatalf("Search failed: %v", err)
 	}
@@ -58,7 +70,7 @@ func TestClientVersions(t *testing.T) {
 	defer server.Close()
 
 	c := NewClient(WithBaseURL(server.URL))
-	resp, err := c.Versions("com.google.inject", "guice", 20)
+	resp, err := c.Versions(context.Background(), "com.google.inject", "guice", 20, false)
 	if err != nil {
 		t.Fatalf("Versions failed: %v", err)
 	}
@@ -66,3 +78,274 @@ func TestClientVersions(t *testing.T) {
 		t.Fatalf("docs len = %d, want 2", len(resp.Response.Docs))
 	}
 }
+
+func TestClientVersionsPagesPastRowLimit(t *testing.T) {
+	const total = 450
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		rows, _ := strconv.Atoi(r.URL.Query().Get("rows"))
+		if rows > solrMaxRows {
+			t.Errorf("rows = %d, want at most %d", rows, solrMaxRows)
+		}
+		var docs []string
+		for i := start; i < total && i < start+rows; i++ {
+			docs = append(docs, fmt.Sprintf(`{"g":"g","a":"a","v":"1.%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"response":{"numFound":%d,"start":%d,"docs":[%s]}}`, total, start, strings.Join(docs, ","))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	resp, err := c.Versions(context.Background(), "g", "a", 0, false)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if len(resp.Response.Docs) != total || resp.Response.Docs[total-1].Version != "1.449" {
+		t.Errorf("got %d versions, want all %d in order", len(resp.Response.Docs), total)
+	}
+
+	resp, err = c.Versions(context.Background(), "g", "a", 300, false)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 300 {
+		t.Errorf("got %d versions, want 300", len(resp.Response.Docs))
+	}
+}
+
+func TestClientSearchCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("canceled search must not reach the server")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewClient(WithBaseURL(server.URL))
+	if _, err := c.SearchMultimodal(ctx, "guice", 20, 0, false); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestClientRetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"response":{"numFound":0,"docs":[]}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithBackoff(time.Millisecond, time.Millisecond))
+	if _, err := c.Search(context.Background(), "guice", 20, 0, false); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestClientRetriesTimeouts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Stall past the client timeout
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte(`{"response":{"numFound":0,"docs":[]}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithBackoff(time.Millisecond, time.Millisecond))
+	c.httpClient.Timeout = 50 * time.Millisecond
+	if _, err := c.Search(context.Background(), "guice", 20, 0, false); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want a retry after the timeout", calls.Load())
+	}
+}
+
+func TestClientDoesNotRetryCertificateErrors(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// The client does not trust the test server's certificate
+	c := NewClient(WithBaseURL(server.URL), WithBackoff(time.Millisecond, time.Millisecond))
+	_, err := c.Search(context.Background(), "guice", 20, 0, false)
+	var certErr *tls.CertificateVerificationError
+	if !errors.As(err, &certErr) {
+		t.Fatalf("err = %v, want a certificate error", err)
+	}
+	if conns.Load() != 1 {
+		t.Errorf("connections = %d, want 1", conns.Load())
+	}
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		header string
+		want   error
+	}{
+		{http.StatusNotFound, "", ErrNotFound},
+		{http.StatusTooManyRequests, "120", ErrRateLimited},
+		{http.StatusBadGateway, "", ErrUnavailable},
+	}
+	for _, tt := range tests {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			if tt.header != "" {
+				w.Header().Set("Retry-After", tt.header)
+			}
+			w.WriteHeader(tt.status)
+		}))
+
+		c := NewClient(WithBaseURL(server.URL), WithRetries(2), WithBackoff(time.Millisecond, time.Millisecond))
+		_, err := c.Search(context.Background(), "guice", 20, 0, false)
+		server.Close()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: err = %v, want %v", tt.status, err, tt.want)
+		}
+
+		// 404 is final and a long Retry-After is reported instead of waited out
+		wantCalls := int32(3)
+		if tt.status == http.StatusNotFound || tt.header != "" {
+			wantCalls = 1
+		}
+		if calls.Load() != wantCalls {
+			t.Errorf("status %d: calls = %d, want %d", tt.status, calls.Load(), wantCalls)
+		}
+		if tt.header != "" && RetryAfter(err) != 2*time.Minute {
+			t.Errorf("RetryAfter = %v, want 2m", RetryAfter(err))
+		}
+	}
+}
+
+func TestClientConcurrencyLimit(t *testing.T) {
+	var active, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"response":{"numFound":0,"docs":[]}}`))
+	}))
+	defer server.Close()
+
+	// Two backends sharing a limiter share its bound
+	limiter := NewLimiter(2)
+	clients := []*Client{
+		NewClient(WithBaseURL(server.URL), WithLimiter(limiter)),
+		NewClient(WithBaseURL(server.URL), WithLimiter(limiter)),
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients[i%2].Search(context.Background(), "guice", 20, i, false)
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+}
+
+func TestClientCredentialsScoped(t *testing.T) {
+	var gotAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"response":{"numFound":0,"docs":[]}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(
+		WithBaseURL(server.URL+"/search"),
+		WithCredentials(server.URL+"/repo", Credentials{Token: "t0k"}),
+	)
+	c.Search(context.Background(), "guice", 20, 0, false)
+	if body, err := c.open(context.Background(), server.URL+"/repo/g/a/1/a-1.pom"); err == nil {
+		body.Close()
+	}
+
+	if len(gotAuth) != 2 || gotAuth[0] != "" || gotAuth[1] != "Bearer t0k" {
+		t.Errorf("Authorization headers = %q, want none for search and a bearer for the repository", gotAuth)
+	}
+}
+
+func TestClientOffline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"response":{"numFound":1,"docs":[{"id":"g:a","g":"g","a":"a"}]}}`))
+	}))
+	defer server.Close()
+
+	// Entries expire immediately, so the offline answer must be stale
+	cache := NewCache(t.TempDir(), WithTTL(EndpointSearch, time.Nanosecond))
+	c := NewClient(WithBaseURL(server.URL), WithCache(cache))
+	if _, err := c.Search(context.Background(), "a", 20, 0, false); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	cache.SetOffline(true)
+
+	resp, err := c.Search(context.Background(), "a", 20, 0, true)
+	if err != nil {
+		t.Fatalf("offline Search failed: %v", err)
+	}
+	if !resp.Stale || resp.Age <= 0 {
+		t.Errorf("CacheInfo = %+v, want stale with an age", resp.CacheInfo)
+	}
+	if _, err := c.Search(context.Background(), "b", 20, 0, false); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline miss err = %v, want ErrNotCached", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestClientOfflinePOMIsStale(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<project><artifactId>a</artifactId></project>`))
+	}))
+	defer server.Close()
+
+	cache := NewCache(t.TempDir(), WithTTL(EndpointFiles, time.Nanosecond))
+	c := NewClient(WithRepositoryURL(server.URL), WithCache(cache))
+	if _, err := c.POM(context.Background(), "g", "a", "1", false); err != nil {
+		t.Fatalf("POM failed: %v", err)
+	}
+	cache.SetOffline(true)
+
+	pom, err := c.POM(context.Background(), "g", "a", "1", false)
+	if err != nil {
+		t.Fatalf("offline POM failed: %v", err)
+	}
+	if !pom.Stale || pom.Age <= 0 {
+		t.Errorf("CacheInfo = %+v, want stale with an age", pom.CacheInfo)
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArtifactFile names one published file of an artifact version: the main
+// jar, a classifier such as sources or javadoc, or the POM.
+type ArtifactFile struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string
+	// Extension is the file type, "jar" when empty.
+	Extension string
+}
+
+func (f ArtifactFile) ext() string {
+	if f.Extension == "" {
+		return "jar"
+	}
+	return f.Extension
+}
+
+// Path is the file's location in the Maven2 repository layout.
+func (f ArtifactFile) Path() string {
+	return artifactPath(f.GroupID, f.ArtifactID, f.Version, f.Classifier, f.ext())
+}
+
+// Name is the file name, such as "guice-7.0.0-sources.jar".
+func (f ArtifactFile) Name() string {
+	return f.Path()[strings.LastIndex(f.Path(), "/")+1:]
+}
+
+// Downloader is implemented by backends that serve artifact files.
+type Downloader interface {
+	// Download streams f into w and verifies it against the checksum the
+	// repository publishes next to it. progress, if not nil, is called as
+	// bytes arrive; total is -1 when the size is unknown. It returns the
+	// checksum algorithm that verified the file.
+	Download(ctx context.Context, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error)
+}
+
+var (
+	_ Downloader = (*Client)(nil)
+	_ Downloader = (*Nexus)(nil)
+	_ Downloader = (*Artifactory)(nil)
+	_ Downloader = (*Metadata)(nil)
+	_ Downloader = (*Multi)(nil)
+)
+
+// DownloadFile downloads f into dir, which is created if needed. The file
+// only appears under its name once it is verified, so a failed or tampered
+// download never replaces a good copy. It returns the file's path and the
+// checksum algorithm that verified it.
+func DownloadFile(ctx context.Context, d Downloader, f ArtifactFile, dir string, progress func(done, total int64)) (string, string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+	tmp, err := os.CreateTemp(dir, "."+f.Name()+".*.part")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	alg, err := d.Download(ctx, f, tmp, progress)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", "", err
+	}
+	path := filepath.Join(dir, f.Name())
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", "", err
+	}
+	return path, alg, nil
+}
+
+// ErrNoChecksum is returned when a repository publishes no checksum for a
+// downloaded file, so it cannot be verified.
+var ErrNoChecksum = errors.New("no published checksum")
+
+// ChecksumError reports a download that does not match its published
+// checksum.
+type ChecksumError struct {
+	File      string
+	Algorithm string
+	Want      string
+	Got       string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: %s mismatch: published %s, downloaded %s", e.File, e.Algorithm, e.Want, e.Got)
+}
+
+// checksums are tried strongest first; the first one published decides.
+var checksums = []struct {
+	ext string
+	new func() hash.Hash
+}{
+	{"sha512", sha512.New},
+	{"sha256", sha256.New},
+	{"sha1", sha1.New},
+}
+
+func download(ctx context.Context, c *Client, repoURL string, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	fileURL := repoURL + "/" + f.Path()
+	if c.offline(fileURL) {
+		return "", ErrNotCached
+	}
+	body, err := c.openWith(ctx, c.downloads, fileURL)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	hashes := make([]hash.Hash, len(checksums))
+	writers := []io.Writer{w}
+	for i, cs := range checksums {
+		hashes[i] = cs.new()
+		writers = append(writers, hashes[i])
+	}
+	var dst io.Writer = io.MultiWriter(writers...)
+	if progress != nil {
+		total := int64(-1)
+		if rb, ok := body.(releasingBody); ok {
+			total = rb.size
+		}
+		dst = &progressWriter{w: dst, total: total, report: progress}
+	}
+	if _, err := io.Copy(dst, body); err != nil {
+		return "", fmt.Errorf("download %s: %w", f.Name(), err)
+	}
+
+	for i, cs := range checksums {
+		data, _, err := c.getBody(ctx, fileURL+"."+cs.ext, EndpointFiles, false)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		want, got := parseChecksum(data), hex.EncodeToString(hashes[i].Sum(nil))
+		if want != got {
+			return "", &ChecksumError{File: f.Name(), Algorithm: strings.ToUpper(cs.ext), Want: want, Got: got}
+		}
+		return strings.ToUpper(cs.ext), nil
+	}
+	return "", fmt.Errorf("%s: %w", f.Name(), ErrNoChecksum)
+}
+
+// parseChecksum reads a checksum file, which some tools write as
+// "<hex>  <file name>".
+func parseChecksum(data []byte) string {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+type progressWriter struct {
+	w      io.Writer
+	done   int64
+	total  int64
+	report func(done, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	p.report(p.done, p.total)
+	return n, err
+}
+
+func (c *Client) Download(ctx context.Context, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	return download(ctx, c, c.repoURL, f, w, progress)
+}
+
+func (n *Nexus) Download(ctx context.Context, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	return download(ctx, n.client, n.repositoryURL(), f, w, progress)
+}
+
+func (a *Artifactory) Download(ctx context.Context, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	if a.repository == "" {
+		return "", ErrUnsupported
+	}
+	return download(ctx, a.client, a.client.baseURL+"/"+a.repository, f, w, progress)
+}
+
+func (m *Metadata) Download(ctx context.Context, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	return download(ctx, m.client, m.client.baseURL, f, w, progress)
+}
+
+// Download tries each backend in order until one has the file. Only a
+// missing file moves on, so nothing has been written to w by then.
+func (m *Multi) Download(ctx context.Context, f ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	err := ErrUnsupported
+	for _, s := range m.searchers {
+		d, ok := s.(Downloader)
+		if !ok {
+			continue
+		}
+		alg, e := d.Download(ctx, f, w, progress)
+		if e == nil {
+			return alg, nil
+		}
+		if !errors.Is(e, ErrNotFound) && !errors.Is(e, ErrUnsupported) {
+			return "", e
+		}
+		if errors.Is(err, ErrUnsupported) {
+			err = e
+		}
+	}
+	return "", err
+}
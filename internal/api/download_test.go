@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientDownload(t *testing.T) {
+	jar := []byte("PK\x03\x04 not really a jar")
+	sum256 := sha256.Sum256(jar)
+	sum1 := sha1.Sum(jar)
+	files := map[string]string{
+		"/g/a/1.0/a-1.0-sources.jar":        string(jar),
+		"/g/a/1.0/a-1.0-sources.jar.sha256": hex.EncodeToString(sum256[:]) + "  a-1.0-sources.jar\n",
+		"/g/a/1.0/a-1.0-sources.jar.sha1":   hex.EncodeToString(sum1[:]),
+		"/g/a/1.0/a-1.0.jar":                string(jar),
+		"/g/a/1.0/a-1.0.jar.sha1":           "0000000000000000000000000000000000000000",
+		"/g/a/1.0/a-1.0.pom":                "<project/>",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	c := NewClient(WithRepositoryURL(server.URL), WithRetries(0))
+
+	var buf bytes.Buffer
+	var done int64
+	alg, err := c.Download(context.Background(), ArtifactFile{GroupID: "g", ArtifactID: "a", Version: "1.0", Classifier: "sources"}, &buf,
+		func(d, total int64) { done = d })
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if alg != "SHA256" || !bytes.Equal(buf.Bytes(), jar) || done != int64(len(jar)) {
+		t.Errorf("got %s, %d bytes, progress %d; want the strongest published checksum and the whole file", alg, buf.Len(), done)
+	}
+
+	_, err = c.Download(context.Background(), ArtifactFile{GroupID: "g", ArtifactID: "a", Version: "1.0"}, &bytes.Buffer{}, nil)
+	var ce *ChecksumError
+	if !errors.As(err, &ce) || ce.Algorithm != "SHA1" {
+		t.Errorf("tampered jar: err = %v, want a SHA1 ChecksumError", err)
+	}
+
+	_, err = c.Download(context.Background(), ArtifactFile{GroupID: "g", ArtifactID: "a", Version: "1.0", Extension: "pom"}, &bytes.Buffer{}, nil)
+	if !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("unsigned pom: err = %v, want ErrNoChecksum", err)
+	}
+
+	_, err = c.Download(context.Background(), ArtifactFile{GroupID: "g", ArtifactID: "a", Version: "2.0"}, &bytes.Buffer{}, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing jar: err = %v, want ErrNotFound", err)
+	}
+}
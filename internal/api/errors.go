@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited")
+	ErrUnavailable = errors.New("service unavailable")
+	// ErrNotCached is returned in offline mode when the cache has no answer.
+	ErrNotCached = errors.New("not available offline")
+)
+
+// StatusError reports a non-200 response. It matches ErrNotFound,
+// ErrRateLimited or ErrUnavailable with errors.Is depending on the status.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the server's requested back-off, zero when not given.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("unexpected status: %d (retry after %s)", e.StatusCode, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("unexpected status: %d", e.StatusCode)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnavailable:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// RetryAfter extracts the server's requested back-off from err, if any.
+func RetryAfter(err error) time.Duration {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.RetryAfter
+	}
+	return 0
+}
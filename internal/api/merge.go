@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// stream is one ordered result list of a merged search. It is read in
+// chunks of a fixed size from offset 0, so every page of the merged search
+// asks for the same chunks and all but the newest come from the cache.
+type stream struct {
+	fetch func(start int) (*SearchResponse, error)
+	chunk int
+	// filter drops docs after fetching; nil keeps all of them.
+	filter *Query
+
+	docs  []Doc
+	next  int
+	total int
+	done  bool
+	err   error
+	info  CacheInfo
+}
+
+// more fetches the next chunk.
+func (s *stream) more() {
+	resp, err := s.fetch(s.next)
+	if err != nil {
+		s.err = err
+		s.done = true
+		return
+	}
+	got := resp.Response.Docs
+	s.info.add(resp.CacheInfo)
+	s.next += len(got)
+	s.total = resp.Response.NumFound
+	s.docs = append(s.docs, s.filter.Filter(got)...)
+	if len(got) == 0 || len(got) < s.chunk || s.next >= s.total {
+		s.done = true
+	}
+}
+
+// remaining is how many results the stream has not fetched yet.
+func (s *stream) remaining() int {
+	if s.done || s.total < s.next {
+		return 0
+	}
+	return s.total - s.next
+}
+
+// merge returns the page [start, start+rows) of the union of streams: all
+// of the first stream, then whatever the second adds, and so on. The order
+// only depends on the streams, so consecutive pages neither overlap nor
+// leave gaps.
+func merge(ctx context.Context, streams []*stream, rows, start int) (*SearchResponse, error) {
+	n := start + rows
+
+	// Streams usually have to be read up to n anyway, so do that at once
+	var wg sync.WaitGroup
+	wg.Add(len(streams))
+	for _, s := range streams {
+		go func(s *stream) {
+			defer wg.Done()
+			for !s.done && len(s.docs) < n && ctx.Err() == nil {
+				s.more()
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	// Duplicates can leave the union short; read on where it ran dry
+	var union []Doc
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var short *stream
+		union, short = unite(streams, n)
+		if short == nil {
+			break
+		}
+		short.more()
+	}
+
+	var (
+		resp SearchResponse
+		ok   bool
+	)
+	for _, s := range streams {
+		if s.err == nil || len(s.docs) > 0 {
+			ok = true
+		}
+		resp.add(s.info)
+	}
+	if !ok {
+		return nil, firstError(streams)
+	}
+	resp.Response.NumFound = unionSize(streams)
+	if len(union) < n {
+		// A short page means every stream is exhausted
+		resp.Response.NumFound = len(union)
+	}
+	resp.Response.Start = start
+	resp.Response.Docs = paginate(union, rows, start)
+	return &resp, nil
+}
+
+// unionSize estimates how many docs the union of streams holds. The
+// streams overlap heavily, so adding them up would count the unfetched
+// overlap once per stream: the largest stream counts in full, the others
+// only with the docs seen so far that it lacks.
+func unionSize(streams []*stream) int {
+	var largest *stream
+	for _, s := range streams {
+		if largest == nil || len(s.docs)+s.remaining() > len(largest.docs)+largest.remaining() {
+			largest = s
+		}
+	}
+	if largest == nil {
+		return 0
+	}
+	seen := make(map[string]bool)
+	for _, d := range largest.docs {
+		seen[docKey(d)] = true
+	}
+	size := len(seen) + largest.remaining()
+	for _, s := range streams {
+		for _, d := range s.docs {
+			if key := docKey(d); !seen[key] {
+				seen[key] = true
+				size++
+			}
+		}
+	}
+	return size
+}
+
+// unite concatenates the streams without duplicates up to n docs. If a
+// stream that has more to fetch runs out first, it is returned as short.
+func unite(streams []*stream, n int) (union []Doc, short *stream) {
+	seen := make(map[string]bool)
+	for _, s := range streams {
+		for i := 0; ; i++ {
+			if len(union) >= n {
+				return union, nil
+			}
+			if i >= len(s.docs) {
+				if !s.done {
+					return union, s
+				}
+				break
+			}
+			if key := docKey(s.docs[i]); !seen[key] {
+				seen[key] = true
+				union = append(union, s.docs[i])
+			}
+		}
+	}
+	return union, nil
+}
+
+// firstError picks the error to report when no stream could be read,
+// preferring a real failure over ErrUnsupported.
+func firstError(streams []*stream) error {
+	for _, s := range streams {
+		if s.err != nil && !errors.Is(s.err, ErrUnsupported) {
+			return s.err
+		}
+	}
+	return ErrUnsupported
+}
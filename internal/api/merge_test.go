@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// listStream serves ids from a fixed list in chunks.
+func listStream(ids []string, chunk int) *stream {
+	return &stream{
+		chunk: chunk,
+		fetch: func(start int) (*SearchResponse, error) {
+			var docs []Doc
+			for i := start; i < len(ids) && i < start+chunk; i++ {
+				docs = append(docs, Doc{ID: ids[i]})
+			}
+			return &SearchResponse{Response: ResponseBody{NumFound: len(ids), Docs: docs}}, nil
+		},
+	}
+}
+
+func TestMergePagesAreContiguous(t *testing.T) {
+	var (
+		exact   = []string{"a", "b"}
+		group   = []string{"b", "c", "a", "d"}
+		keyword []string
+	)
+	for i := 0; i < 9; i++ {
+		keyword = append(keyword, fmt.Sprintf("k%d", i))
+	}
+	keyword = append([]string{"d", "a"}, keyword...)
+	want := []string{"a", "b", "c", "d", "k0", "k1", "k2", "k3", "k4", "k5", "k6", "k7", "k8"}
+
+	const rows = 3
+	var got []string
+	for start := 0; start < len(want)+rows; start += rows {
+		streams := []*stream{
+			listStream(exact, rows),
+			listStream(group, rows),
+			listStream(keyword, rows),
+		}
+		resp, err := merge(context.Background(), streams, rows, start)
+		if err != nil {
+			t.Fatalf("merge at %d failed: %v", start, err)
+		}
+		if resp.Response.Start != start {
+			t.Errorf("start = %d, want %d", resp.Response.Start, start)
+		}
+		for _, d := range resp.Response.Docs {
+			got = append(got, d.ID)
+		}
+		if start+rows >= len(want) && resp.Response.NumFound != len(want) {
+			t.Errorf("total at %d = %d, want the union %d", start, resp.Response.NumFound, len(want))
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+}
+
+func TestMergeReportsErrorWhenNothingAnswers(t *testing.T) {
+	failing := &stream{chunk: 10, fetch: func(int) (*SearchResponse, error) { return nil, ErrUnavailable }}
+	unsupported := &stream{chunk: 10, fetch: func(int) (*SearchResponse, error) { return nil, ErrUnsupported }}
+	if _, err := merge(context.Background(), []*stream{unsupported, failing}, 10, 0); err != ErrUnavailable {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestMergeEstimatesOverlappingTotal(t *testing.T) {
+	var ids []string
+	for i := 0; i < 100; i++ {
+		ids = append(ids, fmt.Sprintf("d%d", i))
+	}
+	extra := append(ids[:len(ids):len(ids)], "x")
+	streams := []*stream{listStream(ids, 10), listStream(ids, 10), listStream(extra, 10)}
+	resp, err := merge(context.Background(), streams, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Response.NumFound != 101 {
+		t.Errorf("total = %d, want the union 101, not the sum of the streams", resp.Response.NumFound)
+	}
+
+	// A backend that overstates its total is clamped once a page runs short
+	lying := listStream([]string{"a", "b"}, 10)
+	fetch := lying.fetch
+	lying.fetch = func(start int) (*SearchResponse, error) {
+		resp, err := fetch(start)
+		resp.Response.NumFound = 50
+		return resp, err
+	}
+	if resp, _ := merge(context.Background(), []*stream{lying}, 10, 0); resp.Response.NumFound != 2 {
+		t.Errorf("total = %d after a short page, want 2", resp.Response.NumFound)
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Metadata reads versions straight from maven-metadata.xml files, so it works
+// against any Maven2-layout repository, including plain HTTP and file://
+// mirrors without a search index. It can only answer exact coordinates.
+type Metadata struct {
+	client *Client
+}
+
+func NewMetadata(baseURL string, opts ...Option) *Metadata {
+	opts = append(opts, WithBaseURL(strings.TrimRight(baseURL, "/")))
+	m := &Metadata{client: NewClient(opts...)}
+	if strings.HasPrefix(baseURL, "file://") {
+		m.client.transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	}
+	return m
+}
+
+type mavenMetadata struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Versioning struct {
+		Latest      string   `xml:"latest"`
+		Release     string   `xml:"release"`
+		Versions    []string `xml:"versions>version"`
+		LastUpdated string   `xml:"lastUpdated"`
+	} `xml:"versioning"`
+}
+
+// decodeMetadata maps maven-metadata.xml into version docs, newest first.
+// The file only records when the artifact was last updated, so that
+// timestamp is attached to the latest version and the others carry none.
+func decodeMetadata(r io.Reader) (*SearchResponse, error) {
+	var md mavenMetadata
+	if err := xml.NewDecoder(r).Decode(&md); err != nil {
+		return nil, err
+	}
+
+	v := md.Versioning
+	latest := v.Latest
+	if latest == "" && len(v.Versions) > 0 {
+		latest = v.Versions[len(v.Versions)-1]
+	}
+	release := v.Release
+	if release == "" {
+		release = latest
+	}
+	var updated int64
+	if t, err := time.Parse("20060102150405", v.LastUpdated); err == nil {
+		updated = t.UnixMilli()
+	}
+
+	docs := make([]Doc, 0, len(v.Versions))
+	for i := len(v.Versions) - 1; i >= 0; i-- {
+		version := strings.TrimSpace(v.Versions[i])
+		doc := Doc{
+			ID:            md.GroupID + ":" + md.ArtifactID + ":" + version,
+			GroupID:       md.GroupID,
+			ArtifactID:    md.ArtifactID,
+			Version:       version,
+			LatestVersion: release,
+			VersionCount:  len(v.Versions),
+		}
+		if version == latest {
+			doc.Timestamp = updated
+		}
+		docs = append(docs, doc)
+	}
+
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(docs),
+			Docs:     docs,
+		},
+	}, nil
+}
+
+func (m *Metadata) metadataURL(groupID, artifactID string) string {
+	return m.client.baseURL + "/" + strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID + "/maven-metadata.xml"
+}
+
+func (m *Metadata) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	return nil, ErrUnsupported
+}
+
+// SearchMultimodal answers "g:a" queries with a single artifact doc and
+// reports every other query as unsupported.
+func (m *Metadata) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if q.Group == "" || q.Artifact == "" {
+		return nil, ErrUnsupported
+	}
+	resp, err := m.Versions(ctx, q.Group, q.Artifact, 0, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := aggregate(resp.Response.Docs)
+	if len(artifacts) > 0 {
+		// Prefer <release> over the newest listed version
+		artifacts[0].LatestVersion = resp.Response.Docs[0].LatestVersion
+	}
+	artifacts = q.Filter(artifacts)
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(artifacts),
+			Start:    start,
+			Docs:     paginate(artifacts, rows, start),
+		},
+		CacheInfo: resp.CacheInfo,
+	}, nil
+}
+
+func (m *Metadata) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+	resp, err := m.client.get(ctx, m.metadataURL(groupID, artifactID), EndpointVersions, bypassCache, decodeMetadata)
+	if err != nil {
+		return nil, err
+	}
+	out := *resp
+	out.Response.Docs = paginate(resp.Response.Docs, rows, 0)
+	return &out, nil
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const guiceMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.google.inject</groupId>
+  <artifactId>guice</artifactId>
+  <versioning>
+    <latest>7.0.0-rc1</latest>
+    <release>6.0.0</release>
+    <versions>
+      <version>5.1.0</version>
+      <version>6.0.0</version>
+      <version>7.0.0-rc1</version>
+    </versions>
+    <lastUpdated>20240315120000</lastUpdated>
+  </versioning>
+</metadata>`
+
+func TestMetadataVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo/com/google/inject/guice/maven-metadata.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(guiceMetadata))
+	}))
+	defer server.Close()
+
+	m := NewMetadata(server.URL + "/repo/")
+	resp, err := m.Versions(context.Background(), "com.google.inject", "guice", 2, false)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	docs := resp.Response.Docs
+	if resp.Response.NumFound != 3 || len(docs) != 2 {
+		t.Fatalf("numFound = %d, docs = %d, want 3 and 2", resp.Response.NumFound, len(docs))
+	}
+	if docs[0].Version != "7.0.0-rc1" || docs[0].ID != "com.google.inject:guice:7.0.0-rc1" {
+		t.Errorf("docs[0] = %+v, want newest version first", docs[0])
+	}
+	if docs[0].LatestVersion != "6.0.0" {
+		t.Errorf("latestVersion = %q, want release %q", docs[0].LatestVersion, "6.0.0")
+	}
+	if y := docs[0].Time().Year(); y != 2024 {
+		t.Errorf("latest version year = %d, want 2024", y)
+	}
+	if docs[1].Timestamp != 0 {
+		t.Errorf("older version timestamp = %d, want 0", docs[1].Timestamp)
+	}
+}
+
+func TestMetadataSearchMultimodal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "com", "google", "inject", "guice")
+	os.MkdirAll(path, 0755)
+	os.WriteFile(filepath.Join(path, "maven-metadata.xml"), []byte(guiceMetadata), 0644)
+
+	m := NewMetadata("file://" + filepath.ToSlash(dir))
+	resp, err := m.SearchMultimodal(context.Background(), "com.google.inject:guice", 20, 0, false)
+	if err != nil {
+		t.Fatalf("SearchMultimodal failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 1 {
+		t.Fatalf("docs len = %d, want 1", len(resp.Response.Docs))
+	}
+	doc := resp.Response.Docs[0]
+	if doc.ID != "com.google.inject:guice" || doc.LatestVersion != "6.0.0" || doc.VersionCount != 3 {
+		t.Errorf("doc = %+v, want guice release 6.0.0 with 3 versions", doc)
+	}
+
+	if _, err := m.SearchMultimodal(context.Background(), "guice", 20, 0, false); err != ErrUnsupported {
+		t.Errorf("keyword search err = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// nexusMaxPages bounds how many continuation pages a single call walks.
+const nexusMaxPages = 20
+
+// Nexus searches a Sonatype Nexus Repository 3 instance through its
+// /service/rest/v1/search API.
+type Nexus struct {
+	client     *Client
+	repository string
+}
+
+func NewNexus(baseURL, repository string, opts ...Option) *Nexus {
+	opts = append(opts, WithBaseURL(strings.TrimRight(baseURL, "/")))
+	return &Nexus{client: NewClient(opts...), repository: repository}
+}
+
+type nexusResponse struct {
+	Items []struct {
+		Group   string `json:"group"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Assets  []struct {
+			Path         string `json:"path"`
+			LastModified string `json:"lastModified"`
+			Maven2       struct {
+				Extension  string `json:"extension"`
+				Classifier string `json:"classifier"`
+			} `json:"maven2"`
+		} `json:"assets"`
+	} `json:"items"`
+	ContinuationToken string `json:"continuationToken"`
+}
+
+func decodeNexus(r io.Reader) (*SearchResponse, error) {
+	var raw nexusResponse
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var docs []Doc
+	for _, item := range raw.Items {
+		doc := Doc{
+			ID:         item.Group + ":" + item.Name + ":" + item.Version,
+			GroupID:    item.Group,
+			ArtifactID: item.Name,
+			Version:    item.Version,
+			Packaging:  "pom",
+		}
+		for _, asset := range item.Assets {
+			if t, err := time.Parse(time.RFC3339, asset.LastModified); err == nil && t.UnixMilli() > doc.Timestamp {
+				doc.Timestamp = t.UnixMilli()
+			}
+			ext := asset.Maven2.Extension
+			if asset.Maven2.Classifier == "" && ext != "" && ext != "pom" && !isChecksumExt(ext) {
+				doc.Packaging = ext
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(docs),
+			Docs:     docs,
+			Cursor:   raw.ContinuationToken,
+		},
+	}, nil
+}
+
+// components walks continuation pages until limit components were collected.
+// The boolean reports whether more pages were left unread.
+func (n *Nexus) components(ctx context.Context, params url.Values, limit int, kind Endpoint, bypassCache bool) ([]Doc, bool, CacheInfo, error) {
+	params.Set("format", "maven2")
+	params.Set("sort", "version")
+	params.Set("direction", "desc")
+	if n.repository != "" {
+		params.Set("repository", n.repository)
+	}
+
+	var (
+		docs []Doc
+		info CacheInfo
+	)
+	for i := 0; i < nexusMaxPages; i++ {
+		reqURL := n.client.baseURL + "/service/rest/v1/search?" + params.Encode()
+		resp, err := n.client.get(ctx, reqURL, kind, bypassCache, decodeNexus)
+		if err != nil {
+			return nil, false, info, err
+		}
+		docs = append(docs, resp.Response.Docs...)
+		info.add(resp.CacheInfo)
+		if resp.Response.Cursor == "" {
+			return docs, false, info, nil
+		}
+		if limit > 0 && len(docs) >= limit {
+			return docs, true, info, nil
+		}
+		params.Set("continuationToken", resp.Response.Cursor)
+	}
+	return docs, true, info, nil
+}
+
+func (n *Nexus) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	return n.search(ctx, params, nil, rows, start, bypassCache)
+}
+
+func (n *Nexus) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return &SearchResponse{}, nil
+	}
+
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if q.Group != "" {
+		params.Set("maven.groupId", q.Group)
+	}
+	if q.Artifact != "" {
+		params.Set("maven.artifactId", q.Artifact)
+	}
+	if len(q.Terms) > 0 {
+		params.Set("q", q.Text())
+	}
+	return n.search(ctx, params, q, rows, start, bypassCache)
+}
+
+// search keeps the artifacts matching filter, which may be nil.
+func (n *Nexus) search(ctx context.Context, params url.Values, filter *Query, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	// Nexus returns one item per version, so fetch generously before
+	// folding them into artifacts.
+	components, more, info, err := n.components(ctx, params, (start+rows)*10, EndpointSearch, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := filter.Filter(aggregate(components))
+	total := len(artifacts)
+	if more {
+		total += rows
+	}
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: total,
+			Start:    start,
+			Docs:     paginate(artifacts, rows, start),
+		},
+		CacheInfo: info,
+	}, nil
+}
+
+func (n *Nexus) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("maven.groupId", groupID)
+	params.Set("maven.artifactId", artifactID)
+
+	docs, _, info, err := n.components(ctx, params, rows, EndpointVersions, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	sortByTimestamp(docs)
+	return &SearchResponse{
+		Response: ResponseBody{
+			NumFound: len(docs),
+			Docs:     paginate(docs, rows, 0),
+		},
+		CacheInfo: info,
+	}, nil
+}
+
+func isChecksumExt(ext string) bool {
+	for _, suffix := range []string{"sha1", "sha256", "sha512", "md5", "asc"} {
+		if strings.HasSuffix(ext, suffix) {
+			return true
+		}
+	}
+	return false
+}
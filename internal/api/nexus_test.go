@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNexusSearchMultimodal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/rest/v1/search" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/service/rest/v1/search")
+		}
+		q := r.URL.Query()
+		if q.Get("maven.groupId") != "com.google.inject" || q.Get("maven.artifactId") != "guice" {
+			t.Errorf("query = %v, want group and artifact filters", q)
+		}
+		if q.Get("repository") != "maven-public" {
+			t.Errorf("repository = %q, want %q", q.Get("repository"), "maven-public")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if q.Get("continuationToken") == "" {
+			w.Write([]byte(`{
+				"items": [{"group":"com.google.inject","name":"guice","version":"7.0.0","assets":[
+					{"path":"com/google/inject/guice/7.0.0/guice-7.0.0.jar","lastModified":"2024-03-15T00:00:00.000+00:00","maven2":{"extension":"jar"}}
+				]}],
+				"continuationToken": "next"
+			}`))
+			return
+		}
+		w.Write([]byte(`{
+			"items": [{"group":"com.google.inject","name":"guice","version":"6.0.0","assets":[
+				{"path":"com/google/inject/guice/6.0.0/guice-6.0.0.jar","lastModified":"2023-05-12T00:00:00.000+00:00","maven2":{"extension":"jar"}}
+			]}],
+			"continuationToken": null
+		}`))
+	}))
+	defer server.Close()
+
+	n := NewNexus(server.URL+"/", "maven-public")
+	resp, err := n.SearchMultimodal(context.Background(), "com.google.inject:guice", 20, 0, false)
+	if err != nil {
+		t.Fatalf("SearchMultimodal failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 1 {
+		t.Fatalf("docs len = %d, want 1", len(resp.Response.Docs))
+	}
+	doc := resp.Response.Docs[0]
+	if doc.ID != "com.google.inject:guice" || doc.LatestVersion != "7.0.0" || doc.VersionCount != 2 {
+		t.Errorf("doc = %+v, want guice 7.0.0 with 2 versions", doc)
+	}
+	if doc.Packaging != "jar" {
+		t.Errorf("packaging = %q, want %q", doc.Packaging, "jar")
+	}
+}
+
+func TestNexusVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items": [
+			{"group":"g","name":"a","version":"1.0","assets":[{"lastModified":"2020-01-01T00:00:00Z"}]},
+			{"group":"g","name":"a","version":"2.0","assets":[{"lastModified":"2022-01-01T00:00:00Z"}]}
+		]}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewNexus(server.URL, "").Versions(context.Background(), "g", "a", 20, false)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 2 || resp.Response.Docs[0].Version != "2.0" {
+		t.Errorf("docs = %+v, want 2.0 first", resp.Response.Docs)
+	}
+}
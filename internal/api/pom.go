@@ -0,0 +1,232 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+const defaultRepositoryURL = "https://repo1.maven.org/maven2"
+
+// POMFetcher downloads the project descriptor of a released artifact.
+type POMFetcher interface {
+	POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*POM, error)
+}
+
+var (
+	_ POMFetcher = (*Client)(nil)
+	_ POMFetcher = (*Nexus)(nil)
+	_ POMFetcher = (*Artifactory)(nil)
+	_ POMFetcher = (*Metadata)(nil)
+	_ POMFetcher = (*Multi)(nil)
+)
+
+type POM struct {
+	GroupID      string          `xml:"groupId"`
+	ArtifactID   string          `xml:"artifactId"`
+	Version      string          `xml:"version"`
+	Packaging    string          `xml:"packaging"`
+	Name         string          `xml:"name"`
+	Description  string          `xml:"description"`
+	URL          string          `xml:"url"`
+	Parent       *Parent         `xml:"parent"`
+	Licenses     []License       `xml:"licenses>license"`
+	SCM          *SCM            `xml:"scm"`
+	Developers   []Developer     `xml:"developers>developer"`
+	Properties   Properties      `xml:"properties"`
+	Dependencies []PomDependency `xml:"dependencies>dependency"`
+
+	DependencyManagement struct {
+		Dependencies []PomDependency `xml:"dependencies>dependency"`
+	} `xml:"dependencyManagement"`
+
+	CacheInfo `xml:"-"`
+}
+
+type Parent struct {
+	GroupID      string `xml:"groupId"`
+	ArtifactID   string `xml:"artifactId"`
+	Version      string `xml:"version"`
+	RelativePath string `xml:"relativePath"`
+}
+
+type License struct {
+	Name string `xml:"name"`
+	URL  string `xml:"url"`
+}
+
+type SCM struct {
+	URL                 string `xml:"url"`
+	Connection          string `xml:"connection"`
+	DeveloperConnection string `xml:"developerConnection"`
+	Tag                 string `xml:"tag"`
+}
+
+type Developer struct {
+	ID           string `xml:"id"`
+	Name         string `xml:"name"`
+	Email        string `xml:"email"`
+	Organization string `xml:"organization"`
+}
+
+type PomDependency struct {
+	GroupID    string      `xml:"groupId"`
+	ArtifactID string      `xml:"artifactId"`
+	Version    string      `xml:"version"`
+	Type       string      `xml:"type"`
+	Classifier string      `xml:"classifier"`
+	Scope      string      `xml:"scope"`
+	Optional   string      `xml:"optional"`
+	Exclusions []Exclusion `xml:"exclusions>exclusion"`
+}
+
+type Exclusion struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+func (d PomDependency) IsOptional() bool {
+	return strings.TrimSpace(d.Optional) == "true"
+}
+
+// Properties holds the free-form <properties> block, keyed by element name.
+type Properties map[string]string
+
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(Properties)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// EffectiveGroupID falls back to the parent's groupId, as Maven does.
+func (p *POM) EffectiveGroupID() string {
+	if p.GroupID == "" && p.Parent != nil {
+		return p.Parent.GroupID
+	}
+	return p.GroupID
+}
+
+func (p *POM) EffectiveVersion() string {
+	if p.Version == "" && p.Parent != nil {
+		return p.Parent.Version
+	}
+	return p.Version
+}
+
+func ParsePOM(r io.Reader) (*POM, error) {
+	var pom POM
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = pomCharsetReader
+	if err := dec.Decode(&pom); err != nil {
+		return nil, fmt.Errorf("parse pom: %w", err)
+	}
+	pom.Name = strings.TrimSpace(pom.Name)
+	pom.Description = strings.Join(strings.Fields(pom.Description), " ")
+	return &pom, nil
+}
+
+// pomCharsetReader decodes the charsets old POMs declare. ISO-8859-1 is
+// read as windows-1252, its superset in practice, as browsers do.
+func pomCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "windows-1252", "cp1252":
+		return charmap.Windows1252.NewDecoder().Reader(input), nil
+	}
+	enc, err := ianaindex.IANA.Encoding(charset)
+	if err != nil || enc == nil {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// artifactPath returns the Maven2 repository layout path of an artifact file,
+// e.g. "com/google/inject/guice/7.0.0/guice-7.0.0.pom".
+func artifactPath(groupID, artifactID, version, classifier, ext string) string {
+	file := artifactID + "-" + version
+	if classifier != "" {
+		file += "-" + classifier
+	}
+	return strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID + "/" + version + "/" + file + "." + ext
+}
+
+func fetchPOM(ctx context.Context, c *Client, repoURL, groupID, artifactID, version string, bypassCache bool) (*POM, error) {
+	data, info, err := c.getBody(ctx, repoURL+"/"+artifactPath(groupID, artifactID, version, "", "pom"), EndpointFiles, bypassCache)
+	if err != nil {
+		return nil, err
+	}
+	pom, err := ParsePOM(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	pom.CacheInfo = info
+	return pom, nil
+}
+
+func (c *Client) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*POM, error) {
+	return fetchPOM(ctx, c, c.repoURL, groupID, artifactID, version, bypassCache)
+}
+
+func (n *Nexus) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*POM, error) {
+	return fetchPOM(ctx, n.client, n.repositoryURL(), groupID, artifactID, version, bypassCache)
+}
+
+// repositoryURL is where the repository's files are served, the public
+// group unless another repository is configured.
+func (n *Nexus) repositoryURL() string {
+	repo := n.repository
+	if repo == "" {
+		repo = "maven-public"
+	}
+	return n.client.baseURL + "/repository/" + repo
+}
+
+func (a *Artifactory) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*POM, error) {
+	if a.repository == "" {
+		return nil, ErrUnsupported
+	}
+	return fetchPOM(ctx, a.client, a.client.baseURL+"/"+a.repository, groupID, artifactID, version, bypassCache)
+}
+
+func (m *Metadata) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*POM, error) {
+	return fetchPOM(ctx, m.client, m.client.baseURL, groupID, artifactID, version, bypassCache)
+}
+
+// POM asks each backend in order and returns the first descriptor found.
+func (m *Multi) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*POM, error) {
+	err := ErrUnsupported
+	for _, s := range m.searchers {
+		f, ok := s.(POMFetcher)
+		if !ok {
+			continue
+		}
+		pom, e := f.POM(ctx, groupID, artifactID, version, bypassCache)
+		if e == nil {
+			return pom, nil
+		}
+		if errors.Is(err, ErrUnsupported) {
+			err = e
+		}
+	}
+	return nil, err
+}
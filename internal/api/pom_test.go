@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const guicePOM = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.google.inject</groupId>
+    <artifactId>guice-parent</artifactId>
+    <version>7.0.0</version>
+  </parent>
+  <artifactId>guice</artifactId>
+  <name>Google Guice - Core Library</name>
+  <description>
+    Guice is a lightweight
+    dependency injection framework.
+  </description>
+  <url>https://github.com/google/guice</url>
+  <licenses>
+    <license><name>The Apache Software License, Version 2.0</name><url>http://www.apache.org/licenses/LICENSE-2.0.txt</url></license>
+  </licenses>
+  <scm><url>https://github.com/google/guice</url><tag>7.0.0</tag></scm>
+  <developers>
+    <developer><id>google</id><name>Google Inc.</name></developer>
+  </developers>
+  <properties>
+    <guava.version>31.0.1-jre</guava.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+      <exclusions>
+        <exclusion><groupId>com.google.code.findbugs</groupId><artifactId>jsr305</artifactId></exclusion>
+      </exclusions>
+    </dependency>
+    <dependency>
+      <groupId>org.ow2.asm</groupId>
+      <artifactId>asm</artifactId>
+      <optional>true</optional>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestParsePOM(t *testing.T) {
+	pom, err := ParsePOM(strings.NewReader(guicePOM))
+	if err != nil {
+		t.Fatalf("ParsePOM failed: %v", err)
+	}
+	if pom.EffectiveGroupID() != "com.google.inject" || pom.EffectiveVersion() != "7.0.0" {
+		t.Errorf("coordinates = %s:%s, want parent values", pom.EffectiveGroupID(), pom.EffectiveVersion())
+	}
+	if pom.Description != "Guice is a lightweight dependency injection framework." {
+		t.Errorf("description = %q", pom.Description)
+	}
+	if len(pom.Licenses) != 1 || !strings.Contains(pom.Licenses[0].Name, "Apache") {
+		t.Errorf("licenses = %+v", pom.Licenses)
+	}
+	if pom.SCM == nil || pom.SCM.Tag != "7.0.0" {
+		t.Errorf("scm = %+v", pom.SCM)
+	}
+	if len(pom.Developers) != 1 || pom.Developers[0].Name != "Google Inc." {
+		t.Errorf("developers = %+v", pom.Developers)
+	}
+	if pom.Properties["guava.version"] != "31.0.1-jre" {
+		t.Errorf("properties = %v", pom.Properties)
+	}
+	if len(pom.Dependencies) != 2 {
+		t.Fatalf("dependencies len = %d, want 2", len(pom.Dependencies))
+	}
+	if len(pom.Dependencies[0].Exclusions) != 1 || pom.Dependencies[0].IsOptional() {
+		t.Errorf("dependencies[0] = %+v", pom.Dependencies[0])
+	}
+	if !pom.Dependencies[1].IsOptional() {
+		t.Error("dependencies[1] should be optional")
+	}
+}
+
+func TestParsePOMLatin1(t *testing.T) {
+	data := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+		"<project><artifactId>a</artifactId><developers><developer><name>J\xf6rg</name></developer></developers></project>"
+	pom, err := ParsePOM(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParsePOM failed: %v", err)
+	}
+	if len(pom.Developers) != 1 || pom.Developers[0].Name != "Jörg" {
+		t.Errorf("developers = %+v", pom.Developers)
+	}
+}
+
+func TestClientPOM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/maven2/com/google/inject/guice/7.0.0/guice-7.0.0.pom" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Write([]byte(guicePOM))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithRepositoryURL(server.URL + "/maven2/"))
+	pom, err := c.POM(context.Background(), "com.google.inject", "guice", "7.0.0", false)
+	if err != nil {
+		t.Fatalf("POM failed: %v", err)
+	}
+	if pom.Name != "Google Guice - Core Library" {
+		t.Errorf("name = %q", pom.Name)
+	}
+}
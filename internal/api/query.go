@@ -2,40 +2,422 @@ package api
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
-func BuildQuery(input string) string {
-	input = strings.TrimSpace(input)
+// Query is a parsed search box query such as
+//
+//	g:io.netty p:jar after:2023-01-01 versions>10 stable "http client"
+//
+// Group, artifact, packaging and terms go to the backend; the rest are also
+// checked client-side by Match, since not every backend can express them.
+type Query struct {
+	Group     string
+	Artifact  string
+	Packaging string
+	// Terms are free words and quoted phrases.
+	Terms []string
 
-	if strings.Contains(input, ":") {
-		parts := strings.SplitN(input, ":", 2)
-		g := strings.TrimSpace(parts[0])
-		a := strings.TrimSpace(parts[1])
-		if g != "" && a != "" {
-			return fmt.Sprintf(`g:"%s" AND a:"%s"`, g, a)
+	After  time.Time
+	Before time.Time
+	// MinVersions and MaxVersions bound the version count; zero is unbounded.
+	MinVersions int
+	MaxVersions int
+	// Stable keeps artifacts whose latest version is not a pre-release.
+	Stable bool
+}
+
+// SyntaxError reports an invalid query. Pos is the byte offset in the input
+// the problem was found at.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Pos+1)
+}
+
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// ParseQuery parses input. A bare "g:a" is coordinates and a bare dotted
+// name like "io.netty" is a group, as before filters existed.
+func ParseQuery(input string) (*Query, error) {
+	q := &Query{}
+	seen := make(map[string]bool)
+	set := func(key string, pos int) error {
+		if seen[key] {
+			return &SyntaxError{Pos: pos, Msg: "duplicate " + key}
+		}
+		seen[key] = true
+		return nil
+	}
+
+	pos := 0
+	for {
+		for pos < len(input) && isSpace(input[pos]) {
+			pos++
+		}
+		if pos >= len(input) {
+			break
+		}
+		start := pos
+		tok, err := scanToken(input, &pos)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(tok, `"`):
+			phrase, err := unquote(tok, start)
+			if err != nil {
+				return nil, err
+			}
+			q.Terms = append(q.Terms, phrase)
+
+		case strings.EqualFold(tok, "stable"):
+			q.Stable = true
+
+		case hasFoldPrefix(tok, "versions") && len(tok) > len("versions") && strings.ContainsRune("<>=", rune(tok[len("versions")])):
+			if err := set("versions", start); err != nil {
+				return nil, err
+			}
+			if err := q.parseVersions(tok[len("versions"):], start+len("versions")); err != nil {
+				return nil, err
+			}
+
+		case strings.Contains(tok, ":"):
+			i := strings.Index(tok, ":")
+			key, raw := strings.ToLower(tok[:i]), tok[i+1:]
+			valuePos := start + i + 1
+			value, err := unquote(raw, valuePos)
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "g", "a", "p", "after", "before":
+				if value == "" {
+					return nil, &SyntaxError{Pos: valuePos, Msg: "missing value for " + key + ":"}
+				}
+				if err := set(key, start); err != nil {
+					return nil, err
+				}
+				if err := q.setField(key, value, valuePos); err != nil {
+					return nil, err
+				}
+			default:
+				// Coordinates, "g:a", ":a" or "g:"
+				if strings.Contains(value, ":") {
+					return nil, &SyntaxError{Pos: valuePos + strings.Index(value, ":"), Msg: `unexpected ":" in coordinates`}
+				}
+				group, _ := unquote(tok[:i], start)
+				if group == "" && value == "" {
+					return nil, &SyntaxError{Pos: start, Msg: "empty coordinates"}
+				}
+				if (group != "" && seen["g"]) || (value != "" && seen["a"]) {
+					return nil, &SyntaxError{Pos: start, Msg: "duplicate coordinates"}
+				}
+				if group != "" {
+					seen["g"] = true
+					q.Group = group
+				}
+				if value != "" {
+					seen["a"] = true
+					q.Artifact = value
+				}
+			}
+
+		case strings.Contains(tok, `"`):
+			return nil, &SyntaxError{Pos: start + strings.Index(tok, `"`), Msg: "unexpected quote"}
+
+		case isGroupName(tok) && !seen["g"]:
+			seen["g"] = true
+			q.Group = tok
+
+		default:
+			q.Terms = append(q.Terms, tok)
+		}
+	}
+
+	if q.Group == "" && q.Artifact == "" && len(q.Terms) == 0 {
+		return nil, &SyntaxError{Pos: len(input), Msg: "nothing to search for"}
+	}
+	if !q.After.IsZero() && !q.Before.IsZero() && !q.After.Before(q.Before) {
+		return nil, &SyntaxError{Pos: strings.Index(strings.ToLower(input), "before:"), Msg: "before: must be later than after:"}
+	}
+	return q, nil
+}
+
+func (q *Query) setField(key, value string, pos int) error {
+	switch key {
+	case "g":
+		q.Group = value
+	case "a":
+		q.Artifact = value
+	case "p":
+		q.Packaging = value
+	case "after", "before":
+		t, ok := parseDate(value)
+		if !ok {
+			return &SyntaxError{Pos: pos, Msg: "invalid date " + strconv.Quote(value) + ", use YYYY-MM-DD"}
+		}
+		if key == "after" {
+			q.After = t
+		} else {
+			q.Before = t
 		}
-		if g != "" {
-			return fmt.Sprintf(`g:"%s"`, g)
+	}
+	return nil
+}
+
+// parseVersions reads the ">10" of "versions>10".
+func (q *Query) parseVersions(s string, pos int) error {
+	op := s[:1]
+	if len(s) > 1 && s[1] == '=' {
+		op = s[:2]
+	}
+	n, err := strconv.Atoi(s[len(op):])
+	if err != nil || n < 0 {
+		return &SyntaxError{Pos: pos + len(op), Msg: "versions needs a number"}
+	}
+	switch op {
+	case ">":
+		q.MinVersions = n + 1
+	case ">=":
+		q.MinVersions = n
+	case "<":
+		q.MaxVersions = n - 1
+	case "<=":
+		q.MaxVersions = n
+	case "=":
+		q.MinVersions, q.MaxVersions = n, n
+	default:
+		return &SyntaxError{Pos: pos, Msg: "unknown operator " + op}
+	}
+	if op[0] != '>' && q.MaxVersions < 1 {
+		return &SyntaxError{Pos: pos, Msg: "every artifact has at least one version"}
+	}
+	return nil
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
 		}
-		if a != "" {
-			return fmt.Sprintf(`a:"%s"`, a)
+	}
+	return time.Time{}, false
+}
+
+// scanToken reads up to the next unquoted space.
+func scanToken(input string, pos *int) (string, error) {
+	start := *pos
+	quoted, quoteAt := false, 0
+	for *pos < len(input) {
+		c := input[*pos]
+		switch {
+		case quoted && c == '\\' && *pos+1 < len(input):
+			*pos++
+		case c == '"':
+			quoted = !quoted
+			quoteAt = *pos
+		case !quoted && isSpace(c):
+			return input[start:*pos], nil
 		}
+		*pos++
+	}
+	if quoted {
+		return "", &SyntaxError{Pos: quoteAt, Msg: "unterminated quote"}
 	}
+	return input[start:], nil
+}
 
-	parts := strings.Split(input, ".")
-	if len(parts) >= 2 && !strings.Contains(input, " ") {
-		allValid := true
-		for _, p := range parts {
-			if p == "" {
-				allValid = false
-				break
+// unquote strips the quotes of a "value" and resolves \" and \\ in it;
+// unquoted values are returned as they are.
+func unquote(s string, pos int) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			if i != len(s)-1 {
+				return "", &SyntaxError{Pos: pos + i + 1, Msg: "unexpected text after quote"}
+			}
+			if b.Len() == 0 {
+				return "", &SyntaxError{Pos: pos, Msg: "empty quotes"}
 			}
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
 		}
-		if allValid {
-			return fmt.Sprintf(`g:"%s"`, input)
+	}
+	return "", &SyntaxError{Pos: pos, Msg: "unterminated quote"}
+}
+
+// isGroupName reports whether s looks like "io.netty".
+func isGroupName(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+		for _, r := range p {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+				return false
+			}
+		}
+	}
+	return unicode.IsLetter(rune(s[0]))
+}
+
+func hasFoldPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// Text is the terms as typed, without quotes, for backends that take a
+// plain keyword.
+func (q *Query) Text() string {
+	return strings.Join(q.Terms, " ")
+}
+
+// Keyword reports whether q is a plain keyword search, which searches
+// artifact and group names as well as free text.
+func (q *Query) Keyword() bool {
+	return q.Group == "" && q.Artifact == "" && len(q.Terms) > 0
+}
+
+// Solr compiles q into a query for the search.maven.org Solr syntax.
+func (q *Query) Solr() string {
+	var terms string
+	for i, t := range q.Terms {
+		if i > 0 {
+			terms += " "
 		}
+		if strings.ContainsAny(t, " \t") {
+			terms += solrQuote(t)
+		} else {
+			terms += solrEscape(t)
+		}
+	}
+	if len(q.Terms) > 1 && len(q.filters()) > 0 {
+		terms = "(" + terms + ")"
 	}
+	return q.with(terms)
+}
 
-	return input
+// with joins clause with the filters of q.
+func (q *Query) with(clause string) string {
+	var clauses []string
+	if clause != "" {
+		clauses = append(clauses, clause)
+	}
+	return strings.Join(append(clauses, q.filters()...), " AND ")
+}
+
+func (q *Query) filters() []string {
+	var out []string
+	if q.Group != "" {
+		out = append(out, "g:"+solrQuote(q.Group))
+	}
+	if q.Artifact != "" {
+		out = append(out, "a:"+solrQuote(q.Artifact))
+	}
+	if q.Packaging != "" {
+		out = append(out, "p:"+solrQuote(q.Packaging))
+	}
+	if !q.After.IsZero() || !q.Before.IsZero() {
+		from, to := "*", "*"
+		if !q.After.IsZero() {
+			from = strconv.FormatInt(q.After.UnixMilli(), 10)
+		}
+		if !q.Before.IsZero() {
+			to = strconv.FormatInt(q.Before.UnixMilli(), 10)
+		}
+		out = append(out, fmt.Sprintf("timestamp:[%s TO %s}", from, to))
+	}
+	if q.MinVersions > 0 || q.MaxVersions > 0 {
+		from, to := "*", "*"
+		if q.MinVersions > 0 {
+			from = strconv.Itoa(q.MinVersions)
+		}
+		if q.MaxVersions > 0 {
+			to = strconv.Itoa(q.MaxVersions)
+		}
+		out = append(out, fmt.Sprintf("versionCount:[%s TO %s]", from, to))
+	}
+	return out
+}
+
+// Match applies the filters backends may not support. Docs that lack the
+// field a filter needs, such as a timestamp, pass it.
+func (q *Query) Match(d Doc) bool {
+	if q.Packaging != "" && d.Packaging != "" && !strings.EqualFold(q.Packaging, d.Packaging) {
+		return false
+	}
+	if d.Timestamp > 0 {
+		t := d.Time()
+		if !q.After.IsZero() && t.Before(q.After) {
+			return false
+		}
+		if !q.Before.IsZero() && !t.Before(q.Before) {
+			return false
+		}
+	}
+	if d.VersionCount > 0 {
+		if q.MinVersions > 0 && d.VersionCount < q.MinVersions {
+			return false
+		}
+		if q.MaxVersions > 0 && d.VersionCount > q.MaxVersions {
+			return false
+		}
+	}
+	if q.Stable && d.IsPreRelease() {
+		return false
+	}
+	return true
+}
+
+// Filter returns the docs that match q. A nil query keeps all of them.
+func (q *Query) Filter(docs []Doc) []Doc {
+	if q == nil {
+		return docs
+	}
+	out := docs[:0:0]
+	for _, d := range docs {
+		if q.Match(d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func solrQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// solrEscape escapes the characters Solr's query parser treats as syntax.
+func solrEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`+-&|!(){}[]^"~*?:\/`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
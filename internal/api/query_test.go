@@ -1,8 +1,11 @@
 package api
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
-func TestBuildQuery(t *testing.T) {
+func TestParseQuerySolr(t *testing.T) {
 	tests := []struct {
 		input string
 		want  string
@@ -13,12 +16,77 @@ func TestBuildQuery(t *testing.T) {
 		{"junit", "junit"},
 		{"org.apache.commons:commons-lang3", `g:"org.apache.commons" AND a:"commons-lang3"`},
 		{"io.netty", `g:"io.netty"`},
+		{`g:io.netty p:jar "http client"`, `"http client" AND g:"io.netty" AND p:"jar"`},
+		{`http client versions>10`, `(http client) AND versionCount:[11 TO *]`},
+		{`after:2023-01-01 guice`, `guice AND timestamp:[1672531200000 TO *}`},
+		{`a:"say \"hi\""`, `a:"say \"hi\""`},
+		{`c++ (foo)`, `c\+\+ \(foo\)`},
+		{`stable guice`, `guice`},
 	}
 
 	for _, tt := range tests {
-		got := BuildQuery(tt.input)
-		if got != tt.want {
-			t.Errorf("BuildQuery(%q) = %q, want %q", tt.input, got, tt.want)
+		q, err := ParseQuery(tt.input)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := q.Solr(); got != tt.want {
+			t.Errorf("ParseQuery(%q).Solr() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{`guice "http client`, 6},
+		{`g:`, 2},
+		{`g:a g:b x`, 4},
+		{`after:yesterday guice`, 6},
+		{`versions>many guice`, 9},
+		{`versions<1 guice`, 8},
+		{`junit:junit:4`, 11},
+		{`gu"ice`, 2},
+		{`stable`, 6},
+		{`guice after:2024-01-01 before:2023-01-01`, 23},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.input)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("ParseQuery(%q) error = %v, want a SyntaxError", tt.input, err)
+			continue
+		}
+		if se.Pos != tt.pos {
+			t.Errorf("ParseQuery(%q) error at %d (%s), want %d", tt.input, se.Pos, se.Msg, tt.pos)
+		}
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	q, err := ParseQuery("netty p:jar after:2023-01-01 versions>=2 stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const jan2024 = 1704067200000
+	tests := []struct {
+		doc  Doc
+		want bool
+	}{
+		{Doc{Packaging: "jar", Timestamp: jan2024, VersionCount: 5, LatestVersion: "4.1.0"}, true},
+		{Doc{Packaging: "pom", Timestamp: jan2024, VersionCount: 5, LatestVersion: "4.1.0"}, false},
+		{Doc{Packaging: "jar", Timestamp: 1, VersionCount: 5, LatestVersion: "4.1.0"}, false},
+		{Doc{Packaging: "jar", Timestamp: jan2024, VersionCount: 1, LatestVersion: "4.1.0"}, false},
+		{Doc{Packaging: "jar", Timestamp: jan2024, VersionCount: 5, LatestVersion: "5.0.0-beta1"}, false},
+		// Backends that do not report a field pass its filter
+		{Doc{LatestVersion: "4.1.0"}, true},
+	}
+	for i, tt := range tests {
+		if got := q.Match(tt.doc); got != tt.want {
+			t.Errorf("case %d: Match = %v, want %v", i, got, tt.want)
 		}
 	}
 }
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetries     = 3
+	defaultConcurrency = 4
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 8 * time.Second
+	// maxRetryAfter is the longest Retry-After we are willing to sit out;
+	// anything longer is reported to the caller straight away.
+	maxRetryAfter = 30 * time.Second
+)
+
+// WithRetries sets how many times a failed request is retried. Zero
+// disables retrying.
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = n }
+}
+
+// WithBackoff sets the base and maximum delay of the exponential backoff.
+func WithBackoff(base, max time.Duration) Option {
+	return func(c *Client) { c.baseDelay, c.maxDelay = base, max }
+}
+
+// Limiter bounds how many requests run at once. Clients that share one
+// share the bound, so several backends together stay within it.
+type Limiter chan struct{}
+
+// NewLimiter allows n requests at once, or the default when n <= 0.
+func NewLimiter(n int) Limiter {
+	if n <= 0 {
+		n = defaultConcurrency
+	}
+	return make(Limiter, n)
+}
+
+// WithConcurrency bounds how many requests the client runs at once, so
+// background prefetching cannot trip a server's rate limiter.
+func WithConcurrency(n int) Option {
+	return WithLimiter(NewLimiter(n))
+}
+
+// WithLimiter makes the client take its request slots from l.
+func WithLimiter(l Limiter) Option {
+	return func(c *Client) {
+		if l != nil {
+			c.slots = l
+		}
+	}
+}
+
+// retryable reports whether err is worth another attempt. Only the
+// caller's ctx ending stops retries: an http.Client timeout also wraps
+// context.DeadlineExceeded, and is exactly what retrying is for.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable) {
+		var se *StatusError
+		return !errors.As(err, &se) || se.StatusCode != http.StatusNotImplemented
+	}
+	// Only transient network failures: a refused connection, an unknown
+	// host or a bad certificate will not get better by asking again
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return connectionDropped(err) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed)
+}
+
+// backoff returns the delay before retry number attempt (starting at 0):
+// the server's Retry-After when given, otherwise full-jitter exponential.
+func (c *Client) backoff(attempt int, err error) time.Duration {
+	if d := RetryAfter(err); d > 0 {
+		return d
+	}
+	ceiling := c.baseDelay << attempt
+	if ceiling <= 0 || ceiling > c.maxDelay {
+		ceiling = c.maxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// acquire takes a concurrency slot; the returned func gives it back.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-c.slots }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// releasingBody frees the concurrency slot once the body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	// size is the Content-Length, -1 when unknown.
+	size int64
+}
+
+func (b releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package api
+
+// connectionDropped has no errno to go by; unexpected EOFs still count.
+func connectionDropped(err error) bool {
+	return false
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package api
+
+import (
+	"errors"
+	"syscall"
+)
+
+// connectionDropped reports whether the server closed the connection on us.
+func connectionDropped(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package api
+
+import (
+	"errors"
+	"syscall"
+)
+
+// connectionDropped reports whether the server closed the connection on us.
+func connectionDropped(err error) bool {
+	return errors.Is(err, syscall.WSAECONNRESET) || errors.Is(err, syscall.WSAECONNABORTED) || errors.Is(err, syscall.ERROR_BROKEN_PIPE)
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Searcher is implemented by every repository backend. Search takes a query
+// in the backend's native syntax, while SearchMultimodal accepts whatever the
+// user typed (keywords, "g:a" or a dotted group) and translates it.
+type Searcher interface {
+	Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error)
+	SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error)
+	Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error)
+}
+
+var (
+	_ Searcher = (*Client)(nil)
+	_ Searcher = (*Nexus)(nil)
+	_ Searcher = (*Artifactory)(nil)
+	_ Searcher = (*Metadata)(nil)
+	_ Searcher = (*Multi)(nil)
+)
+
+var ErrUnsupported = errors.New("operation not supported by backend")
+
+const (
+	BackendCentral     = "central"
+	BackendSolr        = "solr"
+	BackendNexus       = "nexus"
+	BackendArtifactory = "artifactory"
+	BackendMaven2      = "maven2"
+)
+
+// NewBackend builds the Searcher for a configured repository. repository is
+// the repository name used by Nexus and Artifactory and ignored otherwise.
+func NewBackend(kind, baseURL, repository string, opts ...Option) (Searcher, error) {
+	switch kind {
+	case "", BackendCentral:
+		return NewClient(opts...), nil
+	case BackendSolr:
+		if baseURL == "" {
+			return nil, fmt.Errorf("backend %q needs a url", kind)
+		}
+		return NewClient(append(opts, WithBaseURL(baseURL))...), nil
+	case BackendNexus:
+		if baseURL == "" {
+			return nil, fmt.Errorf("backend %q needs a url", kind)
+		}
+		return NewNexus(baseURL, repository, opts...), nil
+	case BackendArtifactory:
+		if baseURL == "" {
+			return nil, fmt.Errorf("backend %q needs a url", kind)
+		}
+		return NewArtifactory(baseURL, repository, opts...), nil
+	case BackendMaven2:
+		if baseURL == "" {
+			return nil, fmt.Errorf("backend %q needs a url", kind)
+		}
+		return NewMetadata(baseURL, opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown backend type: %s", kind)
+	}
+}
+
+// Multi fans every call out to several backends and merges the answers.
+// Results from earlier backends win when the same artifact is returned twice.
+type Multi struct {
+	searchers []Searcher
+}
+
+// NewMulti returns the only searcher unchanged when there is just one.
+func NewMulti(searchers ...Searcher) Searcher {
+	if len(searchers) == 1 {
+		return searchers[0]
+	}
+	return &Multi{searchers: searchers}
+}
+
+func (m *Multi) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	return m.fanOut(ctx, func(s Searcher) (*SearchResponse, error) {
+		return s.Search(ctx, query, rows, start, bypassCache)
+	})
+}
+
+// SearchMultimodal pages through the results of the first backend, then
+// those the next one adds, and so on.
+func (m *Multi) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	streams := make([]*stream, len(m.searchers))
+	for i, s := range m.searchers {
+		streams[i] = &stream{
+			chunk: rows,
+			fetch: func(start int) (*SearchResponse, error) {
+				return s.SearchMultimodal(ctx, query, rows, start, bypassCache)
+			},
+		}
+	}
+	return merge(ctx, streams, rows, start)
+}
+
+func (m *Multi) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+	resp, err := m.fanOut(ctx, func(s Searcher) (*SearchResponse, error) {
+		return s.Versions(ctx, groupID, artifactID, rows, bypassCache)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortByTimestamp(resp.Response.Docs)
+	resp.Response.Docs = paginate(resp.Response.Docs, rows, 0)
+	return resp, nil
+}
+
+func (m *Multi) fanOut(ctx context.Context, call func(Searcher) (*SearchResponse, error)) (*SearchResponse, error) {
+	resps := make([]*SearchResponse, len(m.searchers))
+	errs := make([]error, len(m.searchers))
+
+	var wg sync.WaitGroup
+	wg.Add(len(m.searchers))
+	for i, s := range m.searchers {
+		go func(i int, s Searcher) {
+			defer wg.Done()
+			resps[i], errs[i] = call(s)
+		}(i, s)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		merged SearchResponse
+		seen   = make(map[string]bool)
+		ok     bool
+	)
+	for i, resp := range resps {
+		if errs[i] != nil || resp == nil {
+			continue
+		}
+		ok = true
+		merged.add(resp.CacheInfo)
+		merged.Response.NumFound += resp.Response.NumFound
+		for _, doc := range resp.Response.Docs {
+			key := docKey(doc)
+			if seen[key] {
+				merged.Response.NumFound--
+				continue
+			}
+			seen[key] = true
+			merged.Response.Docs = append(merged.Response.Docs, doc)
+		}
+	}
+	if !ok {
+		for _, err := range errs {
+			if err != nil && !errors.Is(err, ErrUnsupported) {
+				return nil, err
+			}
+		}
+		return nil, ErrUnsupported
+	}
+	return &merged, nil
+}
+
+func docKey(d Doc) string {
+	if d.ID != "" {
+		return d.ID
+	}
+	return d.GroupID + ":" + d.ArtifactID + ":" + d.Version
+}
+
+// aggregate turns per-version docs into one "g:a" doc per artifact, the shape
+// the Solr default core returns. Input must be ordered newest first.
+func aggregate(versions []Doc) []Doc {
+	var (
+		out   []Doc
+		index = make(map[string]int)
+	)
+	for _, v := range versions {
+		id := v.GroupID + ":" + v.ArtifactID
+		if i, ok := index[id]; ok {
+			out[i].VersionCount++
+			if v.Timestamp > out[i].Timestamp {
+				out[i].Timestamp = v.Timestamp
+			}
+			continue
+		}
+		index[id] = len(out)
+		out = append(out, Doc{
+			ID:            id,
+			GroupID:       v.GroupID,
+			ArtifactID:    v.ArtifactID,
+			LatestVersion: v.Version,
+			Packaging:     v.Packaging,
+			Timestamp:     v.Timestamp,
+			VersionCount:  1,
+		})
+	}
+	return out
+}
+
+func paginate(docs []Doc, rows, start int) []Doc {
+	if start >= len(docs) {
+		return nil
+	}
+	end := len(docs)
+	if rows > 0 && start+rows < end {
+		end = start + rows
+	}
+	return docs[start:end]
+}
+
+func sortByTimestamp(docs []Doc) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Timestamp > docs[j].Timestamp
+	})
+}
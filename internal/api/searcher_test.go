@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubSearcher struct {
+	docs []Doc
+	err  error
+}
+
+func (s stubSearcher) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	return s.respond()
+}
+
+func (s stubSearcher) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*SearchResponse, error) {
+	return s.respond()
+}
+
+func (s stubSearcher) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*SearchResponse, error) {
+	return s.respond()
+}
+
+func (s stubSearcher) respond() (*SearchResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &SearchResponse{Response: ResponseBody{NumFound: len(s.docs), Docs: s.docs}}, nil
+}
+
+func TestMultiMergesBackends(t *testing.T) {
+	m := NewMulti(
+		stubSearcher{docs: []Doc{{ID: "g:a"}, {ID: "g:b"}}},
+		stubSearcher{docs: []Doc{{ID: "g:b"}, {ID: "g:c"}}},
+		stubSearcher{err: errors.New("down")},
+	)
+	resp, err := m.SearchMultimodal(context.Background(), "a", 20, 0, false)
+	if err != nil {
+		t.Fatalf("SearchMultimodal failed: %v", err)
+	}
+	if resp.Response.NumFound != 3 || len(resp.Response.Docs) != 3 {
+		t.Errorf("numFound = %d, docs = %d, want 3 and 3", resp.Response.NumFound, len(resp.Response.Docs))
+	}
+}
+
+func TestMultiVersionsSortedAndLimited(t *testing.T) {
+	m := NewMulti(
+		stubSearcher{docs: []Doc{{ID: "g:a:1", Timestamp: 1}}},
+		stubSearcher{docs: []Doc{{ID: "g:a:3", Timestamp: 3}, {ID: "g:a:2", Timestamp: 2}}},
+	)
+	resp, err := m.Versions(context.Background(), "g", "a", 2, false)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if len(resp.Response.Docs) != 2 || resp.Response.Docs[0].ID != "g:a:3" {
+		t.Errorf("docs = %+v, want g:a:3 and g:a:2", resp.Response.Docs)
+	}
+}
+
+func TestMultiAllFailing(t *testing.T) {
+	want := errors.New("down")
+	m := NewMulti(stubSearcher{err: ErrUnsupported}, stubSearcher{err: want})
+	if _, err := m.Versions(context.Background(), "g", "a", 20, false); err != want {
+		t.Errorf("err = %v, want %v", err, want)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	if _, err := NewBackend("nexus", "", ""); err == nil {
+		t.Error("nexus without url should fail")
+	}
+	if _, err := NewBackend("gopher", "http://x", ""); err == nil {
+		t.Error("unknown backend should fail")
+	}
+	s, err := NewBackend("", "", "")
+	if err != nil {
+		t.Fatalf("default backend failed: %v", err)
+	}
+	if _, ok := s.(*Client); !ok {
+		t.Errorf("default backend = %T, want *Client", s)
+	}
+}
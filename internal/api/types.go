@@ -3,16 +3,37 @@ package api
 import (
 	"strings"
 	"time"
+
+	"github.com/maher/mvns/internal/version"
 )
 
 type SearchResponse struct {
-	Response ResponseBody `json:"response"`
+	Response  ResponseBody `json:"response"`
+	CacheInfo `json:"-"`
+}
+
+// CacheInfo describes answers served from the cache in offline mode.
+type CacheInfo struct {
+	// Stale is set when an entry was served past its TTL.
+	Stale bool
+	// Age is how old the oldest cached part of the answer is.
+	Age time.Duration
+}
+
+// add folds the cache state of one part of a merged answer into i.
+func (i *CacheInfo) add(o CacheInfo) {
+	i.Stale = i.Stale || o.Stale
+	if o.Age > i.Age {
+		i.Age = o.Age
+	}
 }
 
 type ResponseBody struct {
 	NumFound int   `json:"numFound"`
 	Start    int   `json:"start"`
 	Docs     []Doc `json:"docs"`
+	// Cursor is set by backends that page with continuation tokens.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 type Doc struct {
@@ -24,24 +45,22 @@ type Doc struct {
 	Packaging     string `json:"p"`
 	Timestamp     int64  `json:"timestamp"`
 	VersionCount  int    `json:"versionCount"`
+	// Classes lists the matching class names of a class search.
+	Classes []string `json:"classes,omitempty"`
 }
 
 func (d Doc) Time() time.Time {
 	return time.UnixMilli(d.Timestamp)
 }
 
+// IsPreRelease classifies the doc's version, or its latest version for
+// artifact results, by Maven's qualifier rules.
 func (d Doc) IsPreRelease() bool {
-	v := strings.ToLower(d.Version)
+	v := d.Version
 	if v == "" {
-		v = strings.ToLower(d.LatestVersion)
-	}
-	prefixes := []string{"-rc", "-beta", "-alpha", "-snapshot", "-dev", "-m"}
-	for _, p := range prefixes {
-		if strings.Contains(v, p) {
-			return true
-		}
+		v = d.LatestVersion
 	}
-	return false
+	return version.IsPreRelease(v)
 }
 
 func (d Doc) DetectScope() string {
@@ -97,6 +97,12 @@ func TestDocIsPreRelease(t *testing.T) {
 		{"7.0.0-dev", true},
 		{"7.0.0-M1", true},
 		{"1.0.0.Final", false},
+		{"7.0.0.CR1", true},
+		{"7.0.0.Beta2", true},
+		{"21-ea", true},
+		{"1.0-preview", true},
+		{"1.0-migration", false},
+		{"33.0.0-jre", false},
 	}
 
 	for _, tt := range tests {
@@ -7,14 +7,44 @@ import (
 )
 
 type Config struct {
-	Lang  string `json:"lang"`
-	Theme string `json:"theme"`
+	Lang       string    `json:"lang"`
+	Theme      string    `json:"theme"`
+	LiveSearch bool      `json:"live_search,omitempty"`
+	Offline    bool      `json:"offline,omitempty"`
+	Backends   []Backend `json:"backends,omitempty"`
+	// Retries is how often a failed request is retried; 0 disables retrying.
+	Retries int `json:"retries"`
+	// Concurrency bounds parallel requests across all backends.
+	Concurrency int `json:"concurrency"`
+	// Settings overrides the path of Maven's settings.xml.
+	Settings string `json:"settings,omitempty"`
+	// CABundle is a PEM file of extra certificate authorities to trust.
+	CABundle string `json:"ca_bundle,omitempty"`
+	// CacheMaxMB bounds the on-disk cache; 0 keeps the default.
+	CacheMaxMB int `json:"cache_max_mb,omitempty"`
+	// CacheTTL overrides cache lifetimes per endpoint ("search", "versions"
+	// or "files") as Go durations such as "30m" or "720h".
+	CacheTTL map[string]string `json:"cache_ttl,omitempty"`
+	// Rank is the default result ordering: relevance, recency, popularity
+	// or alphabetical.
+	Rank string `json:"rank,omitempty"`
+}
+
+// Backend describes one repository to search. Type is one of central, solr,
+// nexus, artifactory or maven2; an empty list means Maven Central only.
+type Backend struct {
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type"`
+	URL        string `json:"url,omitempty"`
+	Repository string `json:"repository,omitempty"`
 }
 
 func Default() Config {
 	return Config{
-		Lang:  "en",
-		Theme: "dark",
+		Lang:        "en",
+		Theme:       "dark",
+		Retries:     3,
+		Concurrency: 4,
 	}
 }
 
@@ -0,0 +1,61 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bazel formats dependencies for rules_jvm_external: the coordinate for
+// maven.install in MODULE.bazel or WORKSPACE, and the label BUILD targets
+// depend on.
+type Bazel struct{}
+
+func (b *Bazel) Name() string { return "Bazel" }
+
+func (b *Bazel) Lexer() string { return "python" }
+
+func (b *Bazel) Format(dep Dependency) string {
+	return b.FormatBlock([]Dependency{dep})
+}
+
+func (b *Bazel) FormatBlock(deps []Dependency) string {
+	var out strings.Builder
+	out.WriteString("# MODULE.bazel or WORKSPACE\nmaven.install(\n    artifacts = [\n")
+	for _, d := range deps {
+		fmt.Fprintf(&out, "        \"%s:%s:%s\",\n", d.GroupID, d.ArtifactID, d.Version)
+	}
+	out.WriteString("    ],\n)\n\n# BUILD\n")
+
+	var mainDeps, testDeps []string
+	for _, d := range deps {
+		label := fmt.Sprintf("    \"%s\",", BazelLabel(d))
+		if d.Scope == "test" {
+			testDeps = append(testDeps, label)
+		} else {
+			mainDeps = append(mainDeps, label)
+		}
+	}
+	var lists []string
+	if len(mainDeps) > 0 {
+		lists = append(lists, "deps = [\n"+strings.Join(mainDeps, "\n")+"\n],")
+	}
+	if len(testDeps) > 0 {
+		lists = append(lists, "# java_test\ndeps = [\n"+strings.Join(testDeps, "\n")+"\n],")
+	}
+	out.WriteString(strings.Join(lists, "\n"))
+	return out.String()
+}
+
+// BazelLabel is the target rules_jvm_external generates for dep in the
+// default "maven" repository, e.g. @maven//:com_google_guava_guava.
+func BazelLabel(dep Dependency) string {
+	return "@maven//:" + bazelEscape(dep.GroupID+":"+dep.ArtifactID)
+}
+
+// bazelEscape follows rules_jvm_external's escape(): separators become
+// underscores and version ranges are dropped.
+func bazelEscape(s string) string {
+	s = strings.NewReplacer(".", "_", "-", "_", ":", "_", "/", "_", "+", "_", "[", "", "]", "").Replace(s)
+	s, _, _ = strings.Cut(s, ",")
+	return s
+}
@@ -0,0 +1,45 @@
+package formatter
+
+import "testing"
+
+func TestBazelLabel(t *testing.T) {
+	tests := []struct {
+		dep  Dependency
+		want string
+	}{
+		{Dependency{GroupID: "com.google.guava", ArtifactID: "guava"}, "@maven//:com_google_guava_guava"},
+		{Dependency{GroupID: "org.junit.jupiter", ArtifactID: "junit-jupiter-api"}, "@maven//:org_junit_jupiter_junit_jupiter_api"},
+		{Dependency{GroupID: "org.scala-lang", ArtifactID: "scala3-library_3"}, "@maven//:org_scala_lang_scala3_library_3"},
+	}
+	for _, tt := range tests {
+		if got := BazelLabel(tt.dep); got != tt.want {
+			t.Errorf("BazelLabel(%s:%s) = %q, want %q", tt.dep.GroupID, tt.dep.ArtifactID, got, tt.want)
+		}
+	}
+}
+
+func TestBazelFormat(t *testing.T) {
+	got := Block(&Bazel{}, []Dependency{
+		{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre"},
+		{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Scope: "test"},
+	})
+	want := `# MODULE.bazel or WORKSPACE
+maven.install(
+    artifacts = [
+        "com.google.guava:guava:33.0.0-jre",
+        "junit:junit:4.13.2",
+    ],
+)
+
+# BUILD
+deps = [
+    "@maven//:com_google_guava_guava",
+],
+# java_test
+deps = [
+    "@maven//:junit_junit",
+],`
+	if got != want {
+		t.Errorf("Block(Bazel):\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VersionCatalog formats entries of a Gradle version catalog,
+// gradle/libs.versions.toml.
+type VersionCatalog struct{}
+
+func (v *VersionCatalog) Name() string { return "Version Catalog" }
+
+func (v *VersionCatalog) Lexer() string { return "toml" }
+
+func (v *VersionCatalog) Format(dep Dependency) string {
+	return v.FormatBlock([]Dependency{dep})
+}
+
+func (v *VersionCatalog) FormatBlock(deps []Dependency) string {
+	c := ParseCatalog(nil)
+	for _, d := range deps {
+		c.Add(d)
+	}
+	return strings.TrimRight(string(c.Bytes()), "\n")
+}
+
+// reservedAliases cannot start a catalog alias, since Gradle generates
+// accessors of these names itself.
+var reservedAliases = map[string]bool{"bundles": true, "versions": true, "plugins": true}
+
+// CatalogAlias derives the catalog alias of dep from its artifactId, e.g.
+// "jackson-databind" or "cats-core" for cats-core_2.13. Segments Gradle
+// would reject, those starting with a digit, are joined to the one before:
+// log4j-1.2-api becomes log4j12-api.
+func CatalogAlias(dep Dependency) string {
+	base, _ := ScalaSuffix(dep.ArtifactID)
+	fields := strings.FieldsFunc(strings.ToLower(base), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	var segs []string
+	for _, f := range fields {
+		switch {
+		case f[0] >= '0' && f[0] <= '9' && len(segs) > 0:
+			segs[len(segs)-1] += f
+		case f[0] >= '0' && f[0] <= '9':
+			segs = append(segs, "v"+f)
+		default:
+			segs = append(segs, f)
+		}
+	}
+	if len(segs) == 0 {
+		return "lib"
+	}
+	if reservedAliases[segs[0]] {
+		segs = append([]string{groupSegment(dep.GroupID)}, segs...)
+	}
+	return strings.Join(segs, "-")
+}
+
+// groupSegment is the last part of a groupId usable in an alias, such as
+// "typelevel" for org.typelevel.
+func groupSegment(groupID string) string {
+	parts := strings.Split(strings.ToLower(groupID), ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		seg := strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, parts[i])
+		if seg != "" && seg[0] >= 'a' && seg[0] <= 'z' {
+			return seg
+		}
+	}
+	return "lib"
+}
+
+// CatalogAccessor is the build script accessor of a catalog alias:
+// jackson-databind is libs.jackson.databind.
+func CatalogAccessor(alias string) string {
+	return "libs." + strings.ReplaceAll(alias, "-", ".")
+}
+
+// catalogReference formats dep through its catalog accessor, as
+// implementation(libs.guava) in Kotlin or implementation libs.guava in
+// Groovy.
+func catalogReference(dep Dependency, kotlin bool) string {
+	accessor := CatalogAccessor(CatalogAlias(dep))
+	if kotlin {
+		return fmt.Sprintf("%s(%s)", gradleConfiguration(dep.Scope), accessor)
+	}
+	return fmt.Sprintf("%s %s", gradleConfiguration(dep.Scope), accessor)
+}
@@ -0,0 +1,182 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Catalog is a libs.versions.toml file. It is edited line by line, so
+// comments and layout survive an insert.
+type Catalog struct {
+	lines []string
+}
+
+func ParseCatalog(data []byte) *Catalog {
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return &Catalog{}
+	}
+	return &Catalog{lines: strings.Split(text, "\n")}
+}
+
+func (c *Catalog) Bytes() []byte {
+	if len(c.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(c.lines, "\n") + "\n")
+}
+
+var (
+	catalogHeader = regexp.MustCompile(`^\s*\[([^\]]+)\]`)
+	catalogEntry  = regexp.MustCompile(`^\s*"?([A-Za-z0-9_.-]+)"?\s*=\s*(.*)$`)
+	moduleField   = regexp.MustCompile(`module\s*=\s*"([^"]+)"`)
+	groupField    = regexp.MustCompile(`group\s*=\s*"([^"]+)"`)
+	nameField     = regexp.MustCompile(`name\s*=\s*"([^"]+)"`)
+	stringValue   = regexp.MustCompile(`^"([^"]*)"`)
+)
+
+// section returns the lines of a table: the header's index and the index
+// after its last entry. ok is false when the table is missing.
+func (c *Catalog) section(name string) (header, end int, ok bool) {
+	header = -1
+	for i, l := range c.lines {
+		m := catalogHeader.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+		if header >= 0 {
+			break
+		}
+		if strings.TrimSpace(m[1]) == name {
+			header, end = i, i+1
+		}
+	}
+	if header < 0 {
+		return 0, 0, false
+	}
+	for i := header + 1; i < len(c.lines) && catalogHeader.FindStringSubmatch(c.lines[i]) == nil; i++ {
+		if t := strings.TrimSpace(c.lines[i]); t != "" {
+			end = i + 1
+		}
+	}
+	return header, end, true
+}
+
+// entries maps the keys of a table to their raw values.
+func (c *Catalog) entries(name string) map[string]string {
+	out := make(map[string]string)
+	header, end, ok := c.section(name)
+	if !ok {
+		return out
+	}
+	for _, l := range c.lines[header+1 : end] {
+		if m := catalogEntry.FindStringSubmatch(l); m != nil && !strings.HasPrefix(strings.TrimSpace(l), "#") {
+			out[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return out
+}
+
+// libraryModule reads the group:artifact of a [libraries] value in any of
+// its notations.
+func libraryModule(value string) string {
+	if m := moduleField.FindStringSubmatch(value); m != nil {
+		return m[1]
+	}
+	g, n := groupField.FindStringSubmatch(value), nameField.FindStringSubmatch(value)
+	if g != nil && n != nil {
+		return g[1] + ":" + n[1]
+	}
+	if m := stringValue.FindStringSubmatch(value); m != nil {
+		parts := strings.Split(m[1], ":")
+		if len(parts) >= 2 {
+			return parts[0] + ":" + parts[1]
+		}
+	}
+	return ""
+}
+
+// insert adds line at the end of a table, creating the table before
+// [libraries] for [versions] and at the end otherwise.
+func (c *Catalog) insert(name, line string) {
+	if _, end, ok := c.section(name); ok {
+		c.lines = append(c.lines[:end], append([]string{line}, c.lines[end:]...)...)
+		return
+	}
+	block := []string{"[" + name + "]", line}
+	if name == "versions" {
+		if header, _, ok := c.section("libraries"); ok {
+			c.lines = append(c.lines[:header], append(append(block, ""), c.lines[header:]...)...)
+			return
+		}
+	}
+	if len(c.lines) > 0 {
+		block = append([]string{""}, block...)
+	}
+	c.lines = append(c.lines, block...)
+}
+
+// Add inserts dep into [libraries] and its version into [versions]. A
+// library already in the catalog is left alone; alias is then its
+// existing alias and added is false. Aliases taken by another library get
+// the group as prefix, and a version ref is shared with an existing one of
+// the same value whose name the alias extends, as jackson for
+// jackson-databind.
+func (c *Catalog) Add(dep Dependency) (alias string, added bool) {
+	module := dep.GroupID + ":" + dep.ArtifactID
+	libraries := c.entries("libraries")
+	for a, v := range libraries {
+		if libraryModule(v) == module {
+			return a, false
+		}
+	}
+
+	alias = uniqueAlias(CatalogAlias(dep), dep.GroupID, libraries)
+
+	versions := c.entries("versions")
+	ref := ""
+	for key, v := range versions {
+		value, k := strings.Trim(v, `"`), catalogKey(key)
+		if value != dep.Version || (k != alias && !strings.HasPrefix(alias, k+"-")) {
+			continue
+		}
+		// The closest name wins
+		if len(key) > len(ref) || len(key) == len(ref) && key < ref {
+			ref = key
+		}
+	}
+	if ref == "" {
+		ref = uniqueAlias(alias, dep.GroupID, versions)
+		c.insert("versions", ref+` = "`+dep.Version+`"`)
+	}
+	c.insert("libraries", alias+` = { module = "`+module+`", version.ref = "`+ref+`" }`)
+	return alias, true
+}
+
+// catalogKey normalises an alias the way Gradle compares them: -, _ and .
+// are the same separator and case does not matter.
+func catalogKey(alias string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(alias))
+}
+
+// uniqueAlias returns alias, or a variant of it that no key of taken
+// clashes with.
+func uniqueAlias(alias, groupID string, taken map[string]string) string {
+	keys := make(map[string]bool, len(taken))
+	for k := range taken {
+		keys[catalogKey(k)] = true
+	}
+	if !keys[catalogKey(alias)] {
+		return alias
+	}
+	prefixed := groupSegment(groupID) + "-" + alias
+	if !keys[catalogKey(prefixed)] {
+		return prefixed
+	}
+	for suffix := 'b'; ; suffix++ {
+		candidate := prefixed + "-" + string(suffix)
+		if !keys[catalogKey(candidate)] {
+			return candidate
+		}
+	}
+}
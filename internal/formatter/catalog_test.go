@@ -0,0 +1,130 @@
+package formatter
+
+import "testing"
+
+func TestCatalogAlias(t *testing.T) {
+	tests := []struct {
+		dep  Dependency
+		want string
+	}{
+		{Dependency{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-databind"}, "jackson-databind"},
+		{Dependency{GroupID: "org.typelevel", ArtifactID: "cats-core_2.13"}, "cats-core"},
+		{Dependency{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-1.2-api"}, "log4j12-api"},
+		{Dependency{GroupID: "io.vertx", ArtifactID: "vertx_core"}, "vertx-core"},
+		{Dependency{GroupID: "org.example", ArtifactID: "versions-maven"}, "example-versions-maven"},
+	}
+	for _, tt := range tests {
+		if got := CatalogAlias(tt.dep); got != tt.want {
+			t.Errorf("CatalogAlias(%s) = %q, want %q", tt.dep.ArtifactID, got, tt.want)
+		}
+	}
+	if got := CatalogAccessor("jackson-databind"); got != "libs.jackson.databind" {
+		t.Errorf("CatalogAccessor = %q", got)
+	}
+}
+
+func TestVersionCatalogFormat(t *testing.T) {
+	dep := Dependency{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre", Scope: "test"}
+	want := `[versions]
+guava = "33.0.0-jre"
+
+[libraries]
+guava = { module = "com.google.guava:guava", version.ref = "guava" }`
+	if got := (&VersionCatalog{}).Format(dep); got != want {
+		t.Errorf("VersionCatalog.Format():\n%s\nwant:\n%s", got, want)
+	}
+	if got := (&GradleKotlin{Catalog: true}).Format(dep); got != "testImplementation(libs.guava)" {
+		t.Errorf("GradleKotlin catalog = %q", got)
+	}
+	if got := (&GradleGroovy{Catalog: true}).Format(dep); got != "testImplementation libs.guava" {
+		t.Errorf("GradleGroovy catalog = %q", got)
+	}
+}
+
+func TestCatalogAdd(t *testing.T) {
+	c := ParseCatalog([]byte(`# shared versions
+[versions]
+jackson = "2.15.2"
+cats-core = "2.9.0"
+
+[libraries]
+jackson-core = { module = "com.fasterxml.jackson.core:jackson-core", version.ref = "jackson" }
+cats-core = "org.example:cats-core:1.0"
+guava = { group = "com.google.guava", name = "guava", version = "32.0.0-jre" }
+
+[plugins]
+kotlin = { id = "org.jetbrains.kotlin.jvm", version = "1.9.0" }
+`))
+
+	if alias, added := c.Add(Dependency{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre"}); added || alias != "guava" {
+		t.Errorf("guava: alias %q, added %v; want the existing entry", alias, added)
+	}
+	if alias, added := c.Add(Dependency{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-databind", Version: "2.15.2"}); !added || alias != "jackson-databind" {
+		t.Errorf("jackson-databind: alias %q, added %v", alias, added)
+	}
+	// cats-core is taken by another module and its version by another value
+	if alias, _ := c.Add(Dependency{GroupID: "org.typelevel", ArtifactID: "cats-core_3", Version: "2.10.0"}); alias != "typelevel-cats-core" {
+		t.Errorf("cats-core: alias %q, want typelevel-cats-core", alias)
+	}
+
+	want := `# shared versions
+[versions]
+jackson = "2.15.2"
+cats-core = "2.9.0"
+typelevel-cats-core = "2.10.0"
+
+[libraries]
+jackson-core = { module = "com.fasterxml.jackson.core:jackson-core", version.ref = "jackson" }
+cats-core = "org.example:cats-core:1.0"
+guava = { group = "com.google.guava", name = "guava", version = "32.0.0-jre" }
+jackson-databind = { module = "com.fasterxml.jackson.core:jackson-databind", version.ref = "jackson" }
+typelevel-cats-core = { module = "org.typelevel:cats-core_3", version.ref = "typelevel-cats-core" }
+
+[plugins]
+kotlin = { id = "org.jetbrains.kotlin.jvm", version = "1.9.0" }
+`
+	if got := string(c.Bytes()); got != want {
+		t.Errorf("catalog:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCatalogAddCreatesVersions(t *testing.T) {
+	c := ParseCatalog([]byte("[libraries]\nguava = \"com.google.guava:guava:33.0.0-jre\"\n"))
+	c.Add(Dependency{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.9"})
+	want := `[versions]
+slf4j-api = "2.0.9"
+
+[libraries]
+guava = "com.google.guava:guava:33.0.0-jre"
+slf4j-api = { module = "org.slf4j:slf4j-api", version.ref = "slf4j-api" }
+`
+	if got := string(c.Bytes()); got != want {
+		t.Errorf("catalog:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCatalogAddNormalisesAliases(t *testing.T) {
+	c := ParseCatalog([]byte(`[versions]
+Jackson_Core = "2.15.2"
+
+[libraries]
+jackson_databind = "org.example:jackson-databind:1.0"
+`))
+	alias, _ := c.Add(Dependency{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-databind", Version: "2.15.2"})
+	if alias != "core-jackson-databind" {
+		t.Errorf("alias %q clashes with jackson_databind", alias)
+	}
+	alias, _ = c.Add(Dependency{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-core", Version: "2.15.2"})
+	want := `[versions]
+Jackson_Core = "2.15.2"
+core-jackson-databind = "2.15.2"
+
+[libraries]
+jackson_databind = "org.example:jackson-databind:1.0"
+core-jackson-databind = { module = "com.fasterxml.jackson.core:jackson-databind", version.ref = "core-jackson-databind" }
+jackson-core = { module = "com.fasterxml.jackson.core:jackson-core", version.ref = "Jackson_Core" }
+`
+	if got := string(c.Bytes()); alias != "jackson-core" || got != want {
+		t.Errorf("alias %q, catalog:\n%s\nwant:\n%s", alias, got, want)
+	}
+}
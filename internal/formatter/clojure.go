@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cljLib is the library symbol Clojure tools read as group/artifact.
+// short allows the bare artifact when it equals the group, so ring stands
+// for ring/ring.
+func cljLib(dep Dependency, short bool) string {
+	if short && dep.GroupID == dep.ArtifactID {
+		return dep.ArtifactID
+	}
+	return dep.GroupID + "/" + dep.ArtifactID
+}
+
+// DepsEdn formats dependencies for the Clojure CLI. tools.deps deprecated
+// unqualified lib names, so ring is always written ring/ring.
+type DepsEdn struct{}
+
+func (d *DepsEdn) Name() string { return "deps.edn" }
+
+func (d *DepsEdn) Lexer() string { return "clojure" }
+
+func (d *DepsEdn) entry(dep Dependency) string {
+	return fmt.Sprintf(`%s {:mvn/version "%s"}`, cljLib(dep, false), dep.Version)
+}
+
+// Format leaves the scope to the map the entry is pasted into; FormatBlock
+// puts test and provided dependencies under aliases.
+func (d *DepsEdn) Format(dep Dependency) string {
+	return "{" + d.entry(dep) + "}"
+}
+
+func (d *DepsEdn) FormatBlock(deps []Dependency) string {
+	var main, test, provided []string
+	for _, dep := range deps {
+		switch dep.Scope {
+		case "test":
+			test = append(test, d.entry(dep))
+		case "provided":
+			provided = append(provided, d.entry(dep))
+		default:
+			main = append(main, d.entry(dep))
+		}
+	}
+	// Entries line up after the brace of their map, which follows the
+	// text before it on the first line
+	m := func(before string, entries []string) string {
+		return before + "{" + strings.Join(entries, "\n"+strings.Repeat(" ", len(before)+1)) + "}"
+	}
+	var sections []string
+	if len(main) > 0 {
+		sections = append(sections, strings.TrimPrefix(m(" :deps ", main), " "))
+	}
+	var aliases []string
+	const open = " :aliases {"
+	if len(test) > 0 {
+		aliases = append(aliases, strings.TrimPrefix(m(open+":test {:extra-deps ", test), open)+"}")
+	}
+	if len(provided) > 0 {
+		aliases = append(aliases, strings.TrimPrefix(m(open+":provided {:extra-deps ", provided), open)+"}")
+	}
+	if len(aliases) > 0 {
+		sections = append(sections, ":aliases {"+strings.Join(aliases, "\n"+strings.Repeat(" ", len(open)))+"}")
+	}
+	return "{" + strings.Join(sections, "\n ") + "}"
+}
+
+// Leiningen formats the vectors of a project.clj :dependencies list.
+type Leiningen struct{}
+
+func (l *Leiningen) Name() string { return "Leiningen" }
+
+func (l *Leiningen) Lexer() string { return "clojure" }
+
+func (l *Leiningen) Format(dep Dependency) string {
+	v := fmt.Sprintf(`[%s "%s"`, cljLib(dep, true), dep.Version)
+	if dep.Scope != "" && dep.Scope != "compile" {
+		v += fmt.Sprintf(` :scope "%s"`, dep.Scope)
+	}
+	return v + "]"
+}
+
+func (l *Leiningen) FormatBlock(deps []Dependency) string {
+	lines := make([]string, len(deps))
+	for i, d := range deps {
+		lines[i] = l.Format(d)
+	}
+	const open = ":dependencies ["
+	return open + strings.Join(lines, "\n"+strings.Repeat(" ", len(open))) + "]"
+}
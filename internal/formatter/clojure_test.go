@@ -0,0 +1,43 @@
+package formatter
+
+import "testing"
+
+func TestClojureFormats(t *testing.T) {
+	ring := Dependency{GroupID: "ring", ArtifactID: "ring", Version: "1.12.1"}
+	json := Dependency{GroupID: "org.clojure", ArtifactID: "data.json", Version: "2.5.0", Scope: "test"}
+
+	tests := []struct {
+		f    Formatter
+		dep  Dependency
+		want string
+	}{
+		{&DepsEdn{}, ring, `{ring/ring {:mvn/version "1.12.1"}}`},
+		{&DepsEdn{}, json, `{org.clojure/data.json {:mvn/version "2.5.0"}}`},
+		{&Leiningen{}, ring, `[ring "1.12.1"]`},
+		{&Leiningen{}, json, `[org.clojure/data.json "2.5.0" :scope "test"]`},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Format(tt.dep); got != tt.want {
+			t.Errorf("%s.Format(%s) = %q, want %q", tt.f.Name(), tt.dep.ArtifactID, got, tt.want)
+		}
+	}
+}
+
+func TestDepsEdnBlock(t *testing.T) {
+	deps := []Dependency{
+		{GroupID: "ring", ArtifactID: "ring", Version: "1.12.1"},
+		{GroupID: "org.clojure", ArtifactID: "data.json", Version: "2.5.0"},
+		{GroupID: "lambdaisland", ArtifactID: "kaocha", Version: "1.91.1392", Scope: "test"},
+		{GroupID: "javax.servlet", ArtifactID: "servlet-api", Version: "2.5", Scope: "provided"},
+	}
+	want := `{:deps {ring/ring {:mvn/version "1.12.1"}
+        org.clojure/data.json {:mvn/version "2.5.0"}}
+ :aliases {:test {:extra-deps {lambdaisland/kaocha {:mvn/version "1.91.1392"}}}
+           :provided {:extra-deps {javax.servlet/servlet-api {:mvn/version "2.5"}}}}}`
+	if got := Block(&DepsEdn{}, deps); got != want {
+		t.Errorf("DepsEdn block:\n%s\nwant:\n%s", got, want)
+	}
+	if got := Block(&DepsEdn{}, deps[2:3]); got != `{:aliases {:test {:extra-deps {lambdaisland/kaocha {:mvn/version "1.91.1392"}}}}}` {
+		t.Errorf("DepsEdn test-only block = %s", got)
+	}
+}
@@ -1,5 +1,7 @@
 package formatter
 
+import "strings"
+
 type Dependency struct {
 	GroupID    string
 	ArtifactID string
@@ -13,10 +15,113 @@ type Formatter interface {
 	Lexer() string
 }
 
+// BlockFormatter is implemented by formatters whose build files group
+// dependencies in an enclosing block.
+type BlockFormatter interface {
+	FormatBlock(deps []Dependency) string
+}
+
+// Block formats deps as one pasteable section of a build file.
+func Block(f Formatter, deps []Dependency) string {
+	if b, ok := f.(BlockFormatter); ok {
+		return b.FormatBlock(deps)
+	}
+	lines := make([]string, len(deps))
+	for i, d := range deps {
+		lines[i] = f.Format(d)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent prefixes every line of s with prefix.
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+// wrapBlock formats each dependency indented inside open and close lines.
+func wrapBlock(f Formatter, deps []Dependency, open, close string) string {
+	var b strings.Builder
+	b.WriteString(open + "\n")
+	for _, d := range deps {
+		b.WriteString(indent(f.Format(d), "    ") + "\n")
+	}
+	b.WriteString(close)
+	return b.String()
+}
+
+// gradleConfiguration maps a Maven scope to a Gradle configuration.
+func gradleConfiguration(scope string) string {
+	switch scope {
+	case "test":
+		return "testImplementation"
+	case "provided":
+		return "compileOnly"
+	case "runtime":
+		return "runtimeOnly"
+	}
+	return "implementation"
+}
+
 func All() []Formatter {
 	return []Formatter{
 		&Maven{},
 		&GradleGroovy{},
 		&GradleKotlin{},
+		&VersionCatalog{},
+		&Sbt{},
+		&Mill{},
+		&Bazel{},
+		&JBang{},
+		&Grab{},
+		&KotlinScript{},
+		&Coursier{},
+		&DepsEdn{},
+		&Leiningen{},
+		&Ivy{},
+		&Buildr{},
+	}
+}
+
+// formats maps the --format names accepted on the command line to their
+// formatters.
+var formats = []struct {
+	key string
+	new func() Formatter
+}{
+	{"maven", func() Formatter { return &Maven{} }},
+	{"gradle", func() Formatter { return &GradleGroovy{} }},
+	{"gradle-kts", func() Formatter { return &GradleKotlin{} }},
+	{"catalog", func() Formatter { return &VersionCatalog{} }},
+	{"gradle-catalog", func() Formatter { return &GradleGroovy{Catalog: true} }},
+	{"gradle-kts-catalog", func() Formatter { return &GradleKotlin{Catalog: true} }},
+	{"sbt", func() Formatter { return &Sbt{} }},
+	{"mill", func() Formatter { return &Mill{} }},
+	{"bazel", func() Formatter { return &Bazel{} }},
+	{"jbang", func() Formatter { return &JBang{} }},
+	{"grab", func() Formatter { return &Grab{} }},
+	{"kotlin-script", func() Formatter { return &KotlinScript{} }},
+	{"coursier", func() Formatter { return &Coursier{} }},
+	{"deps-edn", func() Formatter { return &DepsEdn{} }},
+	{"leiningen", func() Formatter { return &Leiningen{} }},
+	{"ivy", func() Formatter { return &Ivy{} }},
+	{"buildr", func() Formatter { return &Buildr{} }},
+}
+
+// Lookup returns the formatter for a --format name.
+func Lookup(key string) (Formatter, bool) {
+	for _, f := range formats {
+		if f.key == key {
+			return f.new(), true
+		}
+	}
+	return nil, false
+}
+
+// Keys lists the --format names, comma separated, for help and errors.
+func Keys() string {
+	keys := make([]string, len(formats))
+	for i, f := range formats {
+		keys[i] = f.key
 	}
+	return strings.Join(keys, ", ")
 }
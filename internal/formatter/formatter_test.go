@@ -56,7 +56,45 @@ func TestGradleKotlinFormat(t *testing.T) {
 
 func TestAllFormatters(t *testing.T) {
 	formatters := All()
-	if len(formatters) != 3 {
-		t.Errorf("All() len = %d, want 3", len(formatters))
+	if len(formatters) != 15 {
+		t.Errorf("All() len = %d, want 15", len(formatters))
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, key := range []string{"maven", "gradle", "gradle-kts", "sbt", "mill", "catalog", "bazel", "jbang", "grab", "kotlin-script", "coursier", "deps-edn", "leiningen", "ivy", "buildr"} {
+		if _, ok := Lookup(key); !ok {
+			t.Errorf("Lookup(%q) failed", key)
+		}
+	}
+	if _, ok := Lookup("ant"); ok {
+		t.Error("Lookup of an unknown format should fail")
+	}
+}
+
+func TestBlock(t *testing.T) {
+	deps := []Dependency{
+		{GroupID: "g", ArtifactID: "a", Version: "1"},
+		{GroupID: "g", ArtifactID: "b", Version: "2", Scope: "test"},
+	}
+	got := Block(&GradleKotlin{}, deps)
+	want := `dependencies {
+    implementation("g:a:1")
+    testImplementation("g:b:2")
+}`
+	if got != want {
+		t.Errorf("Block(GradleKotlin):\n%s\nwant:\n%s", got, want)
+	}
+
+	got = Block(&Maven{}, deps[:1])
+	want = `<dependencies>
+    <dependency>
+        <groupId>g</groupId>
+        <artifactId>a</artifactId>
+        <version>1</version>
+    </dependency>
+</dependencies>`
+	if got != want {
+		t.Errorf("Block(Maven):\n%s\nwant:\n%s", got, want)
 	}
 }
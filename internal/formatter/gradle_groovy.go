@@ -2,20 +2,24 @@ package formatter
 
 import "fmt"
 
-type GradleGroovy struct{}
+type GradleGroovy struct {
+	// Catalog references dependencies through their version catalog
+	// accessor instead of inline coordinates.
+	Catalog bool
+}
 
 func (g *GradleGroovy) Name() string { return "Gradle Groovy" }
 
 func (g *GradleGroovy) Lexer() string { return "groovy" }
 
 func (g *GradleGroovy) Format(dep Dependency) string {
-	config := "implementation"
-	if dep.Scope == "test" {
-		config = "testImplementation"
-	} else if dep.Scope == "provided" {
-		config = "compileOnly"
-	} else if dep.Scope == "runtime" {
-		config = "runtimeOnly"
+	if g.Catalog {
+		return catalogReference(dep, false)
 	}
+	config := gradleConfiguration(dep.Scope)
 	return fmt.Sprintf("%s '%s:%s:%s'", config, dep.GroupID, dep.ArtifactID, dep.Version)
 }
+
+func (g *GradleGroovy) FormatBlock(deps []Dependency) string {
+	return wrapBlock(g, deps, "dependencies {", "}")
+}
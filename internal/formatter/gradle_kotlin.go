@@ -2,20 +2,24 @@ package formatter
 
 import "fmt"
 
-type GradleKotlin struct{}
+type GradleKotlin struct {
+	// Catalog references dependencies through their version catalog
+	// accessor instead of inline coordinates.
+	Catalog bool
+}
 
 func (g *GradleKotlin) Name() string { return "Gradle Kotlin DSL" }
 
 func (g *GradleKotlin) Lexer() string { return "kotlin" }
 
 func (g *GradleKotlin) Format(dep Dependency) string {
-	config := "implementation"
-	if dep.Scope == "test" {
-		config = "testImplementation"
-	} else if dep.Scope == "provided" {
-		config = "compileOnly"
-	} else if dep.Scope == "runtime" {
-		config = "runtimeOnly"
+	if g.Catalog {
+		return catalogReference(dep, true)
 	}
+	config := gradleConfiguration(dep.Scope)
 	return fmt.Sprintf(`%s("%s:%s:%s")`, config, dep.GroupID, dep.ArtifactID, dep.Version)
 }
+
+func (g *GradleKotlin) FormatBlock(deps []Dependency) string {
+	return wrapBlock(g, deps, "dependencies {", "}")
+}
@@ -0,0 +1,48 @@
+package formatter
+
+import "fmt"
+
+// Ivy formats dependencies for an ivy.xml. The Maven scope becomes a conf
+// mapped onto the default configuration Ivy gives Maven modules.
+type Ivy struct{}
+
+func (i *Ivy) Name() string { return "Ivy" }
+
+func (i *Ivy) Lexer() string { return "xml" }
+
+// ivyConf maps a Maven scope to a conf mapping; empty keeps Ivy's default.
+func ivyConf(scope string) string {
+	switch scope {
+	case "compile", "test", "provided", "runtime":
+		return scope + "->default"
+	}
+	return ""
+}
+
+func (i *Ivy) Format(dep Dependency) string {
+	conf := ""
+	if c := ivyConf(dep.Scope); c != "" {
+		conf = fmt.Sprintf(` conf="%s"`, c)
+	}
+	return fmt.Sprintf(`<dependency org="%s" name="%s" rev="%s"%s/>`, dep.GroupID, dep.ArtifactID, dep.Version, conf)
+}
+
+func (i *Ivy) FormatBlock(deps []Dependency) string {
+	return wrapBlock(i, deps, "<dependencies>", "</dependencies>")
+}
+
+// Buildr formats the artifact specs of a Buildr buildfile, which name the
+// packaging between artifact and version.
+type Buildr struct{}
+
+func (b *Buildr) Name() string { return "Buildr" }
+
+func (b *Buildr) Lexer() string { return "ruby" }
+
+func (b *Buildr) Format(dep Dependency) string {
+	task := "compile"
+	if dep.Scope == "test" {
+		task = "test"
+	}
+	return fmt.Sprintf("%s.with '%s:%s:jar:%s'", task, dep.GroupID, dep.ArtifactID, dep.Version)
+}
@@ -0,0 +1,23 @@
+package formatter
+
+import "testing"
+
+func TestIvyFormats(t *testing.T) {
+	tests := []struct {
+		scope, ivy, buildr string
+	}{
+		{"", `<dependency org="org.slf4j" name="slf4j-api" rev="2.0.9"/>`, "compile.with 'org.slf4j:slf4j-api:jar:2.0.9'"},
+		{"compile", `<dependency org="org.slf4j" name="slf4j-api" rev="2.0.9" conf="compile->default"/>`, "compile.with 'org.slf4j:slf4j-api:jar:2.0.9'"},
+		{"test", `<dependency org="org.slf4j" name="slf4j-api" rev="2.0.9" conf="test->default"/>`, "test.with 'org.slf4j:slf4j-api:jar:2.0.9'"},
+		{"runtime", `<dependency org="org.slf4j" name="slf4j-api" rev="2.0.9" conf="runtime->default"/>`, "compile.with 'org.slf4j:slf4j-api:jar:2.0.9'"},
+	}
+	for _, tt := range tests {
+		dep := Dependency{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.9", Scope: tt.scope}
+		if got := (&Ivy{}).Format(dep); got != tt.ivy {
+			t.Errorf("Ivy scope %q = %q, want %q", tt.scope, got, tt.ivy)
+		}
+		if got := (&Buildr{}).Format(dep); got != tt.buildr {
+			t.Errorf("Buildr scope %q = %q, want %q", tt.scope, got, tt.buildr)
+		}
+	}
+}
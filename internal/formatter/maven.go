@@ -19,3 +19,7 @@ func (m *Maven) Format(dep Dependency) string {
     <version>%s</version>%s
 </dependency>`, dep.GroupID, dep.ArtifactID, dep.Version, scopeTag)
 }
+
+func (m *Maven) FormatBlock(deps []Dependency) string {
+	return wrapBlock(m, deps, "<dependencies>", "</dependencies>")
+}
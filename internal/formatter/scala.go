@@ -0,0 +1,109 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// scalaSuffix matches the Scala binary version sbt appends to cross-built
+// artifacts: _2.12, _2.13, _3.
+var scalaSuffix = regexp.MustCompile(`_(2\.1[0-3]|3)$`)
+
+// ScalaSuffix splits a cross-built artifactId such as "cats-core_2.13" into
+// "cats-core" and "2.13". scala is empty for plain Java artifacts.
+func ScalaSuffix(artifactID string) (base, scala string) {
+	m := scalaSuffix.FindStringSubmatchIndex(artifactID)
+	if m == nil {
+		return artifactID, ""
+	}
+	return artifactID[:m[0]], artifactID[m[2]:m[3]]
+}
+
+type Sbt struct{}
+
+func (s *Sbt) Name() string { return "sbt" }
+
+func (s *Sbt) Lexer() string { return "scala" }
+
+// module is the dependency without the += in front, e.g.
+// "org.typelevel" %% "cats-core" % "2.10.0" % Test.
+func (s *Sbt) module(dep Dependency) string {
+	op, name := "%", dep.ArtifactID
+	if base, scala := ScalaSuffix(name); scala != "" {
+		op, name = "%%", base
+	}
+	m := fmt.Sprintf(`"%s" %s "%s" %% "%s"`, dep.GroupID, op, name, dep.Version)
+	switch dep.Scope {
+	case "test":
+		m += " % Test"
+	case "provided":
+		m += " % Provided"
+	case "runtime":
+		m += " % Runtime"
+	}
+	return m
+}
+
+func (s *Sbt) Format(dep Dependency) string {
+	return "libraryDependencies += " + s.module(dep)
+}
+
+func (s *Sbt) FormatBlock(deps []Dependency) string {
+	lines := make([]string, len(deps))
+	for i, d := range deps {
+		lines[i] = "  " + s.module(d)
+	}
+	return "libraryDependencies ++= Seq(\n" + strings.Join(lines, ",\n") + "\n)"
+}
+
+type Mill struct{}
+
+func (m *Mill) Name() string { return "Mill" }
+
+func (m *Mill) Lexer() string { return "scala" }
+
+// Format leaves the scope to the Agg the line is pasted into; FormatBlock
+// sorts dependencies into them.
+func (m *Mill) Format(dep Dependency) string {
+	if base, scala := ScalaSuffix(dep.ArtifactID); scala != "" {
+		return fmt.Sprintf(`ivy"%s::%s:%s"`, dep.GroupID, base, dep.Version)
+	}
+	return fmt.Sprintf(`ivy"%s:%s:%s"`, dep.GroupID, dep.ArtifactID, dep.Version)
+}
+
+// millTargets maps scopes to the module targets that take them.
+var millTargets = []struct{ scope, target string }{
+	{"", "ivyDeps"},
+	{"provided", "compileIvyDeps"},
+	{"runtime", "runIvyDeps"},
+}
+
+func (m *Mill) FormatBlock(deps []Dependency) string {
+	agg := func(target, indent string, deps []Dependency) string {
+		lines := make([]string, len(deps))
+		for i, d := range deps {
+			lines[i] = indent + "  " + m.Format(d)
+		}
+		return indent + "def " + target + " = Agg(\n" + strings.Join(lines, ",\n") + "\n" + indent + ")"
+	}
+	byScope := make(map[string][]Dependency)
+	for _, d := range deps {
+		scope := d.Scope
+		if scope != "test" && scope != "provided" && scope != "runtime" {
+			scope = ""
+		}
+		byScope[scope] = append(byScope[scope], d)
+	}
+
+	var sections []string
+	for _, t := range millTargets {
+		if ds := byScope[t.scope]; len(ds) > 0 {
+			sections = append(sections, agg(t.target, "", ds))
+		}
+	}
+	if ds := byScope["test"]; len(ds) > 0 {
+		sections = append(sections, "object test extends ScalaTests {\n"+agg("ivyDeps", "  ", ds)+"\n}")
+	}
+	return strings.Join(sections, "\n")
+}
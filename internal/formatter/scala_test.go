@@ -0,0 +1,77 @@
+package formatter
+
+import "testing"
+
+func TestScalaSuffix(t *testing.T) {
+	tests := []struct{ in, base, scala string }{
+		{"cats-core_2.13", "cats-core", "2.13"},
+		{"cats-core_3", "cats-core", "3"},
+		{"akka-actor_2.12", "akka-actor", "2.12"},
+		{"guava", "guava", ""},
+		{"foo_2.13-extra", "foo_2.13-extra", ""},
+		{"jackson-module-scala_2.13", "jackson-module-scala", "2.13"},
+	}
+	for _, tt := range tests {
+		base, scala := ScalaSuffix(tt.in)
+		if base != tt.base || scala != tt.scala {
+			t.Errorf("ScalaSuffix(%q) = %q, %q; want %q, %q", tt.in, base, scala, tt.base, tt.scala)
+		}
+	}
+}
+
+func TestSbtFormat(t *testing.T) {
+	f := &Sbt{}
+	tests := []struct {
+		dep  Dependency
+		want string
+	}{
+		{Dependency{GroupID: "org.typelevel", ArtifactID: "cats-core_3", Version: "2.10.0"},
+			`libraryDependencies += "org.typelevel" %% "cats-core" % "2.10.0"`},
+		{Dependency{GroupID: "org.scalameta", ArtifactID: "munit_2.13", Version: "1.0.0", Scope: "test"},
+			`libraryDependencies += "org.scalameta" %% "munit" % "1.0.0" % Test`},
+		{Dependency{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre"},
+			`libraryDependencies += "com.google.guava" % "guava" % "33.0.0-jre"`},
+	}
+	for _, tt := range tests {
+		if got := f.Format(tt.dep); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+
+	got := Block(f, []Dependency{tests[0].dep, tests[1].dep})
+	want := `libraryDependencies ++= Seq(
+  "org.typelevel" %% "cats-core" % "2.10.0",
+  "org.scalameta" %% "munit" % "1.0.0" % Test
+)`
+	if got != want {
+		t.Errorf("Block(Sbt):\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMillFormat(t *testing.T) {
+	f := &Mill{}
+	if got, want := f.Format(Dependency{GroupID: "org.typelevel", ArtifactID: "cats-core_2.13", Version: "2.10.0"}), `ivy"org.typelevel::cats-core:2.10.0"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := f.Format(Dependency{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre"}), `ivy"com.google.guava:guava:33.0.0-jre"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	got := Block(f, []Dependency{
+		{GroupID: "org.typelevel", ArtifactID: "cats-core_3", Version: "2.10.0"},
+		{GroupID: "org.scalameta", ArtifactID: "munit_3", Version: "1.0.0", Scope: "test"},
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.9", Scope: "compile"},
+	})
+	want := `def ivyDeps = Agg(
+  ivy"org.typelevel::cats-core:2.10.0",
+  ivy"org.slf4j:slf4j-api:2.0.9"
+)
+object test extends ScalaTests {
+  def ivyDeps = Agg(
+    ivy"org.scalameta::munit:1.0.0"
+  )
+}`
+	if got != want {
+		t.Errorf("Block(Mill):\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScriptFormatter is implemented by formatters for single-file scripts,
+// which can wrap their dependencies into a runnable skeleton.
+type ScriptFormatter interface {
+	Script(deps []Dependency) string
+}
+
+var (
+	_ ScriptFormatter = (*JBang)(nil)
+	_ ScriptFormatter = (*Grab)(nil)
+	_ ScriptFormatter = (*KotlinScript)(nil)
+	_ ScriptFormatter = (*Coursier)(nil)
+)
+
+// coordinates is the plain g:a:v most script runners take.
+func coordinates(dep Dependency) string {
+	return fmt.Sprintf("%s:%s:%s", dep.GroupID, dep.ArtifactID, dep.Version)
+}
+
+// JBang declares dependencies in //DEPS comments of a Java source file.
+type JBang struct{}
+
+func (j *JBang) Name() string { return "JBang" }
+
+func (j *JBang) Lexer() string { return "java" }
+
+func (j *JBang) Format(dep Dependency) string {
+	return "//DEPS " + coordinates(dep)
+}
+
+func (j *JBang) Script(deps []Dependency) string {
+	return `///usr/bin/env jbang "$0" "$@" ; exit $?
+` + Block(j, deps) + `
+
+public class Main {
+    public static void main(String... args) {
+    }
+}`
+}
+
+// Grab declares dependencies of a Groovy script through Grape.
+type Grab struct{}
+
+func (g *Grab) Name() string { return "Groovy Grape" }
+
+func (g *Grab) Lexer() string { return "groovy" }
+
+func (g *Grab) Format(dep Dependency) string {
+	return fmt.Sprintf("@Grab('%s')", coordinates(dep))
+}
+
+func (g *Grab) Script(deps []Dependency) string {
+	// Annotations need an element; an import is the usual one
+	return "#!/usr/bin/env groovy\n" + Block(g, deps) + "\nimport groovy.transform.Field\n\nprintln 'ready'"
+}
+
+// KotlinScript declares dependencies of a .main.kts script.
+type KotlinScript struct{}
+
+func (k *KotlinScript) Name() string { return "Kotlin Script" }
+
+func (k *KotlinScript) Lexer() string { return "kotlin" }
+
+func (k *KotlinScript) Format(dep Dependency) string {
+	return fmt.Sprintf(`@file:DependsOn("%s")`, coordinates(dep))
+}
+
+func (k *KotlinScript) Script(deps []Dependency) string {
+	return "#!/usr/bin/env kotlin\n" + Block(k, deps) + "\n\nprintln(\"ready\")"
+}
+
+// Coursier launches an artifact's main class with cs launch. Scala
+// artifacts use cs's g::a:v form, so it picks the Scala version itself.
+type Coursier struct{}
+
+func (c *Coursier) Name() string { return "Coursier" }
+
+func (c *Coursier) Lexer() string { return "bash" }
+
+func (c *Coursier) module(dep Dependency) string {
+	if base, scala := ScalaSuffix(dep.ArtifactID); scala != "" {
+		return fmt.Sprintf("%s::%s:%s", dep.GroupID, base, dep.Version)
+	}
+	return coordinates(dep)
+}
+
+func (c *Coursier) Format(dep Dependency) string {
+	return "cs launch " + c.module(dep)
+}
+
+func (c *Coursier) FormatBlock(deps []Dependency) string {
+	modules := make([]string, len(deps))
+	for i, d := range deps {
+		modules[i] = c.module(d)
+	}
+	return "cs launch " + strings.Join(modules, " ")
+}
+
+func (c *Coursier) Script(deps []Dependency) string {
+	return "#!/usr/bin/env sh\n# Add --main-class <class> if the first jar has no Main-Class\nexec " + c.FormatBlock(deps) + ` -- "$@"`
+}
@@ -0,0 +1,54 @@
+package formatter
+
+import "testing"
+
+func TestScriptFormats(t *testing.T) {
+	dep := Dependency{GroupID: "info.picocli", ArtifactID: "picocli", Version: "4.7.5"}
+	tests := []struct {
+		f    Formatter
+		want string
+	}{
+		{&JBang{}, "//DEPS info.picocli:picocli:4.7.5"},
+		{&Grab{}, "@Grab('info.picocli:picocli:4.7.5')"},
+		{&KotlinScript{}, `@file:DependsOn("info.picocli:picocli:4.7.5")`},
+		{&Coursier{}, "cs launch info.picocli:picocli:4.7.5"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Format(dep); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.f.Name(), got, tt.want)
+		}
+	}
+
+	scala := Dependency{GroupID: "com.lihaoyi", ArtifactID: "ammonite_2.13.12", Version: "3.0.0"}
+	if got := (&Coursier{}).Format(Dependency{GroupID: "org.scalameta", ArtifactID: "scalafmt-cli_2.13", Version: "3.7.17"}); got != "cs launch org.scalameta::scalafmt-cli:3.7.17" {
+		t.Errorf("Coursier Scala = %q", got)
+	}
+	if got := (&Coursier{}).Format(scala); got != "cs launch com.lihaoyi:ammonite_2.13.12:3.0.0" {
+		t.Errorf("full Scala versions are not cross-built suffixes: %q", got)
+	}
+}
+
+func TestScriptSkeleton(t *testing.T) {
+	deps := []Dependency{
+		{GroupID: "info.picocli", ArtifactID: "picocli", Version: "4.7.5"},
+		{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre"},
+	}
+	want := `///usr/bin/env jbang "$0" "$@" ; exit $?
+//DEPS info.picocli:picocli:4.7.5
+//DEPS com.google.guava:guava:33.0.0-jre
+
+public class Main {
+    public static void main(String... args) {
+    }
+}`
+	if got := (&JBang{}).Script(deps); got != want {
+		t.Errorf("JBang script:\n%s\nwant:\n%s", got, want)
+	}
+
+	want = `#!/usr/bin/env sh
+# Add --main-class <class> if the first jar has no Main-Class
+exec cs launch info.picocli:picocli:4.7.5 com.google.guava:guava:33.0.0-jre -- "$@"`
+	if got := (&Coursier{}).Script(deps); got != want {
+		t.Errorf("Coursier script:\n%s\nwant:\n%s", got, want)
+	}
+}
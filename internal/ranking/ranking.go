@@ -0,0 +1,243 @@
+// Package ranking orders search results. The CLI and the TUI share its
+// strategies, so both list the same artifact first.
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+// Factor is one named contribution to a score.
+type Factor struct {
+	Name   string
+	Points float64
+}
+
+// Ranked is a doc with its score under a strategy.
+type Ranked struct {
+	Doc   api.Doc
+	Score float64
+	// Factors explain Score, largest first.
+	Factors []Factor
+}
+
+// Explain renders the factors as "exact artifact +100, popularity +9.5".
+func (r Ranked) Explain() string {
+	parts := make([]string, len(r.Factors))
+	for i, f := range r.Factors {
+		parts[i] = fmt.Sprintf("%s %+.1f", f.Name, f.Points)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Strategy is a named way of ordering results.
+type Strategy struct {
+	Key   string
+	score func(d api.Doc, m match) []Factor
+}
+
+var (
+	Relevance    = Strategy{Key: "relevance", score: relevance}
+	Recency      = Strategy{Key: "recency", score: recency}
+	Popularity   = Strategy{Key: "popularity", score: popularity}
+	Alphabetical = Strategy{Key: "alphabetical"}
+)
+
+var strategies = []Strategy{Relevance, Recency, Popularity, Alphabetical}
+
+func Lookup(key string) (Strategy, bool) {
+	for _, s := range strategies {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Strategy{}, false
+}
+
+// Keys lists the strategy keys for help texts.
+func Keys() string {
+	keys := make([]string, len(strategies))
+	for i, s := range strategies {
+		keys[i] = s.Key
+	}
+	return strings.Join(keys, ", ")
+}
+
+// Next is the strategy after s, for cycling through them.
+func (s Strategy) Next() Strategy {
+	for i, o := range strategies {
+		if o.Key == s.Key {
+			return strategies[(i+1)%len(strategies)]
+		}
+	}
+	return Relevance
+}
+
+// Rank scores docs for query and orders them best first. Ties, and every
+// doc under Alphabetical, are ordered by groupId:artifactId.
+func (s Strategy) Rank(docs []api.Doc, query string) []Ranked {
+	m := newMatch(query, time.Now())
+	out := make([]Ranked, len(docs))
+	for i, d := range docs {
+		out[i].Doc = d
+		if s.score == nil {
+			continue
+		}
+		out[i].Factors = s.score(d, m)
+		sort.SliceStable(out[i].Factors, func(a, b int) bool {
+			return out[i].Factors[a].Points > out[i].Factors[b].Points
+		})
+		for _, f := range out[i].Factors {
+			out[i].Score += f.Points
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return name(out[i].Doc) < name(out[j].Doc)
+	})
+	return out
+}
+
+// Sort is Rank without the scores.
+func (s Strategy) Sort(docs []api.Doc, query string) []api.Doc {
+	ranked := s.Rank(docs, query)
+	out := make([]api.Doc, len(ranked))
+	for i, r := range ranked {
+		out[i] = r.Doc
+	}
+	return out
+}
+
+func name(d api.Doc) string {
+	return strings.ToLower(d.GroupID + ":" + d.ArtifactID)
+}
+
+// officialGroups publish the canonical artifacts of their ecosystems, so
+// their results beat forks and repackagings with similar names.
+var officialGroups = []string{
+	"org.apache.", "com.google.", "org.springframework", "io.netty", "com.fasterxml.jackson",
+	"org.junit", "junit", "org.slf4j", "ch.qos.logback", "org.jetbrains.kotlin", "org.jetbrains.kotlinx",
+	"io.micronaut", "io.quarkus", "org.hibernate", "org.eclipse.", "com.squareup", "io.projectreactor",
+	"org.mockito", "org.assertj", "org.scala-lang", "io.grpc", "org.projectlombok", "org.postgresql",
+}
+
+// match is what the scoring functions compare docs against.
+type match struct {
+	// text is the free text or artifact the user searched for.
+	text   string
+	tokens []string
+	now    time.Time
+}
+
+func newMatch(query string, now time.Time) match {
+	text := query
+	if q, err := api.ParseQuery(query); err == nil {
+		if q.Artifact != "" {
+			text = q.Artifact
+		} else {
+			text = q.Text()
+		}
+	}
+	text = strings.ToLower(strings.TrimSpace(text))
+	return match{text: text, tokens: tokenize(text), now: now}
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return strings.ContainsRune(" -_.:", r)
+	})
+}
+
+// similarity is the share of tokens the artifact name and the query have in
+// common (Jaccard index).
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, t := range a {
+		set[t] = true
+	}
+	common, union := 0, len(set)
+	seen := make(map[string]bool)
+	for _, t := range b {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if set[t] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// activity rewards releases within the last two years, most for the
+// newest.
+func activity(d api.Doc, now time.Time) float64 {
+	if d.Timestamp <= 0 {
+		return 0
+	}
+	const window = 2 * 365 * 24 * time.Hour
+	age := now.Sub(d.Time())
+	if age < 0 {
+		age = 0
+	}
+	if age >= window {
+		return 0
+	}
+	return 1 - float64(age)/float64(window)
+}
+
+func relevance(d api.Doc, m match) []Factor {
+	var out []Factor
+	artifact := strings.ToLower(d.ArtifactID)
+	if m.text != "" && artifact == m.text {
+		out = append(out, Factor{"exact artifact", 100})
+	}
+	// org.junit.jupiter matches junit-jupiter
+	group := strings.ReplaceAll(strings.ToLower(d.GroupID), ".", "-")
+	if m.text != "" && strings.Contains(group, strings.ReplaceAll(m.text, ".", "-")) {
+		out = append(out, Factor{"group match", 40})
+	}
+	for _, g := range officialGroups {
+		if strings.HasPrefix(d.GroupID, g) {
+			out = append(out, Factor{"official group", 15})
+			break
+		}
+	}
+	if s := similarity(tokenize(artifact), m.tokens); s > 0 {
+		out = append(out, Factor{"name similarity", 30 * s})
+	}
+	if d.VersionCount > 0 {
+		out = append(out, Factor{"popularity", 3 * math.Log2(float64(d.VersionCount)+1)})
+	}
+	if a := activity(d, m.now); a > 0 {
+		out = append(out, Factor{"recent release", 10 * a})
+	}
+	return out
+}
+
+func recency(d api.Doc, m match) []Factor {
+	if d.Timestamp <= 0 {
+		return nil
+	}
+	// Days since the epoch, so a newer release always wins
+	return []Factor{{"released " + d.Time().Format("2006-01-02"), float64(d.Timestamp) / float64(24*time.Hour/time.Millisecond)}}
+}
+
+func popularity(d api.Doc, m match) []Factor {
+	if d.VersionCount <= 0 {
+		return nil
+	}
+	return []Factor{{"versions", float64(d.VersionCount)}}
+}
@@ -0,0 +1,81 @@
+package ranking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+func ids(docs []api.Doc) string {
+	var out []string
+	for _, d := range docs {
+		out = append(out, d.GroupID+":"+d.ArtifactID)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestRelevance(t *testing.T) {
+	recent := time.Now().Add(-30 * 24 * time.Hour).UnixMilli()
+	docs := []api.Doc{
+		{GroupID: "com.example.fork", ArtifactID: "guice-extras", VersionCount: 90, Timestamp: recent},
+		{GroupID: "org.sonatype.sisu", ArtifactID: "sisu-guice", VersionCount: 30},
+		{GroupID: "com.google.inject", ArtifactID: "guice", VersionCount: 40},
+	}
+	got := ids(Relevance.Sort(docs, "guice"))
+	if !strings.HasPrefix(got, "com.google.inject:guice ") {
+		t.Errorf("order = %s, want the exact artifact first", got)
+	}
+
+	// Group matches beat name similarity alone
+	docs = []api.Doc{
+		{GroupID: "io.github.someone", ArtifactID: "junit-jupiter-extras"},
+		{GroupID: "org.junit.jupiter", ArtifactID: "junit-jupiter-api"},
+	}
+	if got := ids(Relevance.Sort(docs, "junit-jupiter")); !strings.HasPrefix(got, "org.junit.jupiter") {
+		t.Errorf("order = %s, want org.junit.jupiter first", got)
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	docs := []api.Doc{
+		{GroupID: "b", ArtifactID: "old", Timestamp: 1000, VersionCount: 50},
+		{GroupID: "a", ArtifactID: "new", Timestamp: 2000 * 86400000, VersionCount: 2},
+		{GroupID: "c", ArtifactID: "mid", Timestamp: 1500 * 86400000, VersionCount: 10},
+	}
+	tests := []struct {
+		s    Strategy
+		want string
+	}{
+		{Recency, "a:new c:mid b:old"},
+		{Popularity, "b:old c:mid a:new"},
+		{Alphabetical, "a:new b:old c:mid"},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.s.Sort(docs, "x")); got != tt.want {
+			t.Errorf("%s: order = %s, want %s", tt.s.Key, got, tt.want)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	r := Relevance.Rank([]api.Doc{{GroupID: "com.google.inject", ArtifactID: "guice", VersionCount: 7}}, "guice")[0]
+	got := r.Explain()
+	if !strings.HasPrefix(got, "exact artifact +100.0, ") || !strings.Contains(got, "official group +15.0") {
+		t.Errorf("Explain() = %q", got)
+	}
+}
+
+func TestLookupAndNext(t *testing.T) {
+	s, ok := Lookup("recency")
+	if !ok || s.Key != "recency" {
+		t.Fatalf("Lookup(recency) = %v, %v", s, ok)
+	}
+	if _, ok := Lookup("random"); ok {
+		t.Error("Lookup(random) should fail")
+	}
+	if Alphabetical.Next().Key != "relevance" {
+		t.Error("cycling should wrap around")
+	}
+}
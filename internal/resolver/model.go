@@ -0,0 +1,254 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+// rawModel is a POM merged with its parent chain but not yet interpolated,
+// so ${project.version} in a parent still refers to the child.
+type rawModel struct {
+	groupID    string
+	artifactID string
+	version    string
+	parent     *api.Parent
+	props      map[string]string
+	managed    []api.PomDependency
+	deps       []api.PomDependency
+}
+
+// model is the effective POM: interpolated, BOM imports expanded and
+// dependencyManagement applied to the declared dependencies.
+type model struct {
+	managed map[string]api.PomDependency
+	deps    []api.PomDependency
+}
+
+func gav(groupID, artifactID, version string) string {
+	return groupID + ":" + artifactID + ":" + version
+}
+
+func managementKey(d api.PomDependency) string {
+	t := d.Type
+	if t == "" {
+		t = "jar"
+	}
+	return d.GroupID + ":" + d.ArtifactID + ":" + t + ":" + d.Classifier
+}
+
+func (r *Resolver) raw(ctx context.Context, groupID, artifactID, version string, chain map[string]bool) (*rawModel, error) {
+	key := gav(groupID, artifactID, version)
+	if chain[key] {
+		return nil, fmt.Errorf("parent cycle at %s", key)
+	}
+
+	r.mu.Lock()
+	if m, ok := r.raws[key]; ok {
+		r.mu.Unlock()
+		return m, nil
+	}
+	r.mu.Unlock()
+
+	pom, err := r.fetcher.POM(ctx, groupID, artifactID, version, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	m := &rawModel{
+		groupID:    pom.EffectiveGroupID(),
+		artifactID: pom.ArtifactID,
+		version:    pom.EffectiveVersion(),
+		parent:     pom.Parent,
+		props:      make(map[string]string),
+	}
+	if m.groupID == "" {
+		m.groupID = groupID
+	}
+	if m.version == "" {
+		m.version = version
+	}
+
+	if p := pom.Parent; p != nil {
+		chain[key] = true
+		parent, err := r.raw(ctx, p.GroupID, p.ArtifactID, p.Version, chain)
+		delete(chain, key)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range parent.props {
+			m.props[k] = v
+		}
+		m.managed = append(m.managed, parent.managed...)
+		m.deps = append(m.deps, parent.deps...)
+	}
+
+	for k, v := range pom.Properties {
+		m.props[k] = v
+	}
+	m.managed = append(m.managed, pom.DependencyManagement.Dependencies...)
+	m.deps = overrideDeps(m.deps, pom.Dependencies)
+
+	r.mu.Lock()
+	r.raws[key] = m
+	r.mu.Unlock()
+	return m, nil
+}
+
+// overrideDeps appends the child's dependencies, replacing inherited ones
+// with the same management key.
+func overrideDeps(inherited, own []api.PomDependency) []api.PomDependency {
+	out := make([]api.PomDependency, 0, len(inherited)+len(own))
+	index := make(map[string]int)
+	for _, d := range inherited {
+		index[managementKey(d)] = len(out)
+		out = append(out, d)
+	}
+	for _, d := range own {
+		if i, ok := index[managementKey(d)]; ok {
+			out[i] = d
+			continue
+		}
+		index[managementKey(d)] = len(out)
+		out = append(out, d)
+	}
+	return out
+}
+
+func (r *Resolver) effective(ctx context.Context, groupID, artifactID, version string, imports map[string]bool) (*model, error) {
+	key := gav(groupID, artifactID, version)
+
+	r.mu.Lock()
+	if m, ok := r.models[key]; ok {
+		r.mu.Unlock()
+		return m, nil
+	}
+	r.mu.Unlock()
+
+	raw, err := r.raw(ctx, groupID, artifactID, version, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	props := raw.properties()
+	m := &model{managed: make(map[string]api.PomDependency)}
+
+	// Inherited and declared entries first; later declarations win
+	var boms []api.PomDependency
+	for _, d := range raw.managed {
+		d = interpolateDep(d, props)
+		if d.Scope == "import" && d.Type == "pom" {
+			boms = append(boms, d)
+			continue
+		}
+		m.managed[managementKey(d)] = d
+	}
+
+	// Imported BOMs only fill gaps, the first import winning
+	for _, b := range boms {
+		bomKey := gav(b.GroupID, b.ArtifactID, b.Version)
+		if imports[bomKey] {
+			continue
+		}
+		imports[bomKey] = true
+		bom, err := r.effective(ctx, b.GroupID, b.ArtifactID, b.Version, imports)
+		delete(imports, bomKey)
+		if err != nil {
+			return nil, err
+		}
+		for k, d := range bom.managed {
+			if _, ok := m.managed[k]; !ok {
+				m.managed[k] = d
+			}
+		}
+	}
+
+	for _, d := range raw.deps {
+		d = interpolateDep(d, props)
+		if managed, ok := m.managed[managementKey(d)]; ok {
+			if d.Version == "" {
+				d.Version = managed.Version
+			}
+			if d.Scope == "" {
+				d.Scope = managed.Scope
+			}
+			if d.Optional == "" {
+				d.Optional = managed.Optional
+			}
+			// Clip so the append cannot write into an array the raw model shares
+			d.Exclusions = append(slices.Clip(d.Exclusions), managed.Exclusions...)
+		}
+		m.deps = append(m.deps, d)
+	}
+
+	r.mu.Lock()
+	r.models[key] = m
+	r.mu.Unlock()
+	return m, nil
+}
+
+func (m *rawModel) properties() map[string]string {
+	props := make(map[string]string, len(m.props)+8)
+	for k, v := range m.props {
+		props[k] = v
+	}
+	for _, prefix := range []string{"project.", "pom.", ""} {
+		props[prefix+"groupId"] = m.groupID
+		props[prefix+"artifactId"] = m.artifactID
+		props[prefix+"version"] = m.version
+	}
+	if m.parent != nil {
+		props["project.parent.groupId"] = m.parent.GroupID
+		props["project.parent.artifactId"] = m.parent.ArtifactID
+		props["project.parent.version"] = m.parent.Version
+	}
+	return props
+}
+
+func interpolateDep(d api.PomDependency, props map[string]string) api.PomDependency {
+	d.GroupID = interpolate(d.GroupID, props)
+	d.ArtifactID = interpolate(d.ArtifactID, props)
+	d.Version = interpolate(d.Version, props)
+	d.Type = interpolate(d.Type, props)
+	d.Classifier = interpolate(d.Classifier, props)
+	d.Scope = interpolate(d.Scope, props)
+	d.Optional = interpolate(d.Optional, props)
+	return d
+}
+
+// interpolate expands ${name} references. Unknown names are left untouched
+// and nesting is bounded to stop self-referencing properties.
+func interpolate(s string, props map[string]string) string {
+	for depth := 0; depth < 10 && strings.Contains(s, "${"); depth++ {
+		var b strings.Builder
+		changed := false
+		for {
+			start := strings.Index(s, "${")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(s[start:], "}")
+			if end < 0 {
+				break
+			}
+			name := s[start+2 : start+end]
+			b.WriteString(s[:start])
+			if v, ok := props[name]; ok {
+				b.WriteString(v)
+				changed = true
+			} else {
+				b.WriteString(s[start : start+end+1])
+			}
+			s = s[start+end+1:]
+		}
+		b.WriteString(s)
+		s = b.String()
+		if !changed {
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
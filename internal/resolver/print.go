@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"fmt"
+	"io"
+)
+
+// Label formats a node as g:a:v with its scope, like mvn dependency:tree.
+func (n *Node) Label() string {
+	d := n.Dependency
+	label := d.GroupID + ":" + d.ArtifactID + ":" + d.Version
+	if d.Scope != "" && d.Scope != "compile" {
+		label += ":" + d.Scope
+	}
+	return label
+}
+
+// Note explains why a node is incomplete, or returns "" when it is not.
+func (n *Node) Note() string {
+	switch {
+	case n.Omitted && n.Winner == n.Dependency.Version:
+		return "omitted for duplicate"
+	case n.Omitted:
+		return "omitted for conflict with " + n.Winner
+	case n.Err != nil:
+		return "unresolved: " + n.Err.Error()
+	}
+	return ""
+}
+
+// Print writes the tree using mvn dependency:tree's ASCII layout. Omitted
+// nodes are only shown when verbose is set.
+func Print(w io.Writer, root *Node, verbose bool) {
+	fmt.Fprintln(w, root.Label())
+	printChildren(w, root, "", verbose)
+}
+
+func printChildren(w io.Writer, n *Node, indent string, verbose bool) {
+	var children []*Node
+	for _, c := range n.Children {
+		if c.Omitted && !verbose {
+			continue
+		}
+		children = append(children, c)
+	}
+
+	for i, c := range children {
+		branch, next := "+- ", "|  "
+		if i == len(children)-1 {
+			branch, next = "\\- ", "   "
+		}
+		line := c.Label()
+		if note := c.Note(); note != "" {
+			line = "(" + line + " - " + note + ")"
+		}
+		fmt.Fprintln(w, indent+branch+line)
+		printChildren(w, c, indent+next, verbose)
+	}
+}
@@ -0,0 +1,178 @@
+// Package resolver computes the transitive dependency graph of an artifact
+// from its POMs, following Maven's mediation rules.
+package resolver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/formatter"
+)
+
+// maxWorkers bounds how many POMs are fetched at once per tree level.
+const maxWorkers = 8
+
+type Resolver struct {
+	fetcher api.POMFetcher
+
+	mu     sync.Mutex
+	raws   map[string]*rawModel
+	models map[string]*model
+}
+
+func New(fetcher api.POMFetcher) *Resolver {
+	return &Resolver{
+		fetcher: fetcher,
+		raws:    make(map[string]*rawModel),
+		models:  make(map[string]*model),
+	}
+}
+
+// Node is one dependency in the resolved tree. Omitted nodes lost conflict
+// mediation to an earlier, nearer declaration and have no children.
+type Node struct {
+	Dependency formatter.Dependency
+	Optional   bool
+	Children   []*Node
+	Omitted    bool
+	// Winner is the version that was chosen instead of an omitted node.
+	Winner string
+	// Err is set when the node's own POM could not be resolved.
+	Err error
+}
+
+// Count returns the number of resolved dependencies below n.
+func (n *Node) Count() int {
+	count := 0
+	for _, c := range n.Children {
+		if c.Omitted {
+			continue
+		}
+		count += 1 + c.Count()
+	}
+	return count
+}
+
+type pending struct {
+	node       *Node
+	exclusions []api.Exclusion
+	deps       []api.PomDependency
+}
+
+// Resolve builds the tree the way a consumer declaring dep would see it:
+// test, provided and optional dependencies of dependencies are not
+// inherited, exclusions apply to whole subtrees and the nearest declaration
+// of an artifact wins.
+func (r *Resolver) Resolve(ctx context.Context, dep formatter.Dependency) (*Node, error) {
+	if dep.Scope == "" {
+		dep.Scope = "compile"
+	}
+	root := &Node{Dependency: dep}
+
+	m, err := r.effective(ctx, dep.GroupID, dep.ArtifactID, dep.Version, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := map[string]string{dep.GroupID + ":" + dep.ArtifactID: dep.Version}
+	level := []pending{{node: root, deps: m.deps}}
+
+	for len(level) > 0 {
+		var next []pending
+		for _, p := range level {
+			for _, d := range p.deps {
+				if d.Scope == "test" || d.Scope == "provided" || d.Scope == "system" || d.IsOptional() {
+					continue
+				}
+				if excluded(d, p.exclusions) {
+					continue
+				}
+
+				child := &Node{
+					Dependency: formatter.Dependency{
+						GroupID:    d.GroupID,
+						ArtifactID: d.ArtifactID,
+						Version:    d.Version,
+						Scope:      mediateScope(p.node.Dependency.Scope, d.Scope),
+					},
+				}
+				p.node.Children = append(p.node.Children, child)
+
+				key := d.GroupID + ":" + d.ArtifactID
+				if winner, ok := resolved[key]; ok {
+					child.Omitted = true
+					child.Winner = winner
+					continue
+				}
+				resolved[key] = d.Version
+
+				exclusions := append(append([]api.Exclusion{}, p.exclusions...), d.Exclusions...)
+				next = append(next, pending{node: child, exclusions: exclusions})
+			}
+		}
+
+		r.load(ctx, next)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		level = level[:0]
+		for _, p := range next {
+			if p.node.Err == nil {
+				level = append(level, p)
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// load fetches the effective models of a whole tree level concurrently.
+func (r *Resolver) load(ctx context.Context, level []pending) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxWorkers)
+	)
+	for i := range level {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(p *pending) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d := p.node.Dependency
+			m, err := r.effective(ctx, d.GroupID, d.ArtifactID, d.Version, make(map[string]bool))
+			if err != nil {
+				p.node.Err = err
+				return
+			}
+			p.deps = m.deps
+		}(&level[i])
+	}
+	wg.Wait()
+}
+
+func excluded(d api.PomDependency, exclusions []api.Exclusion) bool {
+	for _, e := range exclusions {
+		if (e.GroupID == "*" || e.GroupID == d.GroupID) && (e.ArtifactID == "*" || e.ArtifactID == d.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediateScope applies Maven's scope propagation table for a dependency
+// declared with scope inside a dependency resolved with parent scope.
+func mediateScope(parent, scope string) string {
+	if scope == "" {
+		scope = "compile"
+	}
+	switch parent {
+	case "", "compile":
+		return scope
+	case "runtime":
+		return "runtime"
+	default:
+		// provided and test dependencies keep their scope downstream
+		return parent
+	}
+}
@@ -0,0 +1,166 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/formatter"
+)
+
+type fakeRepo map[string]string
+
+func (f fakeRepo) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*api.POM, error) {
+	raw, ok := f[groupID+":"+artifactID+":"+version]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return api.ParsePOM(strings.NewReader(raw))
+}
+
+func pom(coords, body string) string {
+	parts := strings.Split(coords, ":")
+	return fmt.Sprintf(`<project><groupId>%s</groupId><artifactId>%s</artifactId><version>%s</version>%s</project>`,
+		parts[0], parts[1], parts[2], body)
+}
+
+func dep(g, a, v, extra string) string {
+	return fmt.Sprintf(`<dependency><groupId>%s</groupId><artifactId>%s</artifactId><version>%s</version>%s</dependency>`, g, a, v, extra)
+}
+
+func testRepo() fakeRepo {
+	return fakeRepo{
+		"org.acme:parent:1": pom("org.acme:parent:1", `
+			<properties><lib.version>2.0</lib.version></properties>
+			<dependencyManagement><dependencies>
+				`+dep("org.acme", "bom", "1", "<type>pom</type><scope>import</scope>")+`
+				`+dep("org.lib", "core", "${lib.version}", "")+`
+			</dependencies></dependencyManagement>`),
+		"org.acme:bom:1": pom("org.acme:bom:1", `
+			<dependencyManagement><dependencies>
+				`+dep("org.lib", "core", "9.9", "")+`
+				`+dep("org.lib", "extra", "3.0", "")+`
+			</dependencies></dependencyManagement>`),
+		"org.acme:app:1": `<project>
+			<parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1</version></parent>
+			<artifactId>app</artifactId>
+			<dependencies>
+				<dependency><groupId>org.lib</groupId><artifactId>core</artifactId></dependency>
+				<dependency><groupId>org.lib</groupId><artifactId>extra</artifactId><scope>runtime</scope>
+					<exclusions><exclusion><groupId>org.noise</groupId><artifactId>*</artifactId></exclusion></exclusions>
+				</dependency>
+				` + dep("org.test", "junit", "4", "<scope>test</scope>") + `
+				` + dep("org.opt", "opt", "1", "<optional>true</optional>") + `
+			</dependencies>
+		</project>`,
+		"org.lib:core:2.0": pom("org.lib:core:2.0", `<dependencies>
+			`+dep("org.util", "util", "1.0", "")+`
+		</dependencies>`),
+		"org.lib:extra:3.0": pom("org.lib:extra:3.0", `<dependencies>
+			`+dep("org.util", "util", "0.5", "")+`
+			`+dep("org.noise", "noise", "1", "")+`
+			`+dep("org.lib", "deep", "1", "")+`
+		</dependencies>`),
+		"org.util:util:1.0": pom("org.util:util:1.0", ""),
+		"org.lib:deep:1":    pom("org.lib:deep:1", ""),
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root, err := New(testRepo()).Resolve(context.Background(), formatter.Dependency{GroupID: "org.acme", ArtifactID: "app", Version: "1"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	var b strings.Builder
+	Print(&b, root, true)
+	want := `org.acme:app:1
++- org.lib:core:2.0
+|  \- org.util:util:1.0
+\- org.lib:extra:3.0:runtime
+   +- (org.util:util:0.5:runtime - omitted for conflict with 1.0)
+   \- org.lib:deep:1:runtime
+`
+	if b.String() != want {
+		t.Errorf("tree:\n%s\nwant:\n%s", b.String(), want)
+	}
+	if root.Count() != 4 {
+		t.Errorf("Count() = %d, want 4", root.Count())
+	}
+}
+
+func TestResolveMissingPOM(t *testing.T) {
+	repo := fakeRepo{
+		"g:a:1": pom("g:a:1", `<dependencies>`+dep("g", "missing", "1", "")+`</dependencies>`),
+	}
+	root, err := New(repo).Resolve(context.Background(), formatter.Dependency{GroupID: "g", ArtifactID: "a", Version: "1"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(root.Children) != 1 || root.Children[0].Err == nil {
+		t.Errorf("children = %+v, want one unresolved child", root.Children)
+	}
+
+	if _, err := New(repo).Resolve(context.Background(), formatter.Dependency{GroupID: "g", ArtifactID: "nope", Version: "1"}); err == nil {
+		t.Error("missing root should fail")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	props := map[string]string{"a": "${b}", "b": "1.0", "self": "${self}"}
+	tests := []struct {
+		in, want string
+	}{
+		{"${a}", "1.0"},
+		{"v${b}-x", "v1.0-x"},
+		{"${unknown}", "${unknown}"},
+		{"${self}", "${self}"},
+	}
+	for _, tt := range tests {
+		if got := interpolate(tt.in, props); got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	deps := []formatter.Dependency{
+		{GroupID: "org.util", ArtifactID: "util", Version: "1.0"},
+		{GroupID: "org.lib", ArtifactID: "extra", Version: "3.0"},
+		{GroupID: "org.lib", ArtifactID: "deep", Version: "1"},
+		{GroupID: "org.unknown", ArtifactID: "x", Version: "1"},
+	}
+	roots, covered, err := New(testRepo()).Roots(context.Background(), deps)
+	if err != nil {
+		t.Fatalf("Roots failed: %v", err)
+	}
+
+	var got []string
+	for _, d := range roots {
+		got = append(got, d.ArtifactID)
+	}
+	// util is kept: extra resolves it to 0.5, dropping it would downgrade
+	if strings.Join(got, ",") != "util,extra,x" {
+		t.Errorf("roots = %v, want the pinned util, extra and the unresolvable x", got)
+	}
+	if len(covered) != 2 {
+		t.Fatalf("covered = %+v, want util and deep", covered)
+	}
+	for _, c := range covered {
+		if c.By.ArtifactID != "extra" {
+			t.Errorf("%s covered by %s, want extra", c.Dependency.ArtifactID, c.By.ArtifactID)
+		}
+		switch c.Dependency.ArtifactID {
+		case "util":
+			if !c.Pinned || c.Version != "0.5" {
+				t.Errorf("util = %+v, want pinned over 0.5", c)
+			}
+		case "deep":
+			if c.Pinned {
+				t.Errorf("deep resolves to its own version and should not be pinned")
+			}
+		}
+	}
+}
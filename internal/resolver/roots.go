@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"context"
+	"sort"
+
+	"github.com/maher/mvns/internal/formatter"
+)
+
+// Covered is a dependency that another one already brings in transitively.
+type Covered struct {
+	Dependency formatter.Dependency
+	By         formatter.Dependency
+	// Version is what the tree of By resolves the dependency to. When it
+	// differs from the declared one the dependency is Pinned: it stays
+	// among the roots so the classpath keeps its version.
+	Version string
+	Pinned  bool
+}
+
+// Roots splits deps into the ones that must be declared and the ones the
+// others already pull in at the same version. Dependencies with the
+// largest trees are considered first, so of two that include each other
+// the bigger one stays. A dependency whose tree cannot be resolved is kept
+// and covers nothing.
+func (r *Resolver) Roots(ctx context.Context, deps []formatter.Dependency) ([]formatter.Dependency, []Covered, error) {
+	trees := make([]map[string]string, len(deps))
+	for i, d := range deps {
+		trees[i] = make(map[string]string)
+		root, err := r.Resolve(ctx, d)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			continue
+		}
+		collect(root, trees[i])
+	}
+
+	order := make([]int, len(deps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return len(trees[order[a]]) > len(trees[order[b]]) })
+
+	type provider struct {
+		by      int
+		version string
+	}
+	var (
+		provided = make(map[string]provider)
+		keep     = make([]bool, len(deps))
+		covered  []Covered
+	)
+	for _, i := range order {
+		d := deps[i]
+		if p, ok := provided[d.GroupID+":"+d.ArtifactID]; ok {
+			pinned := p.version != d.Version
+			covered = append(covered, Covered{Dependency: d, By: deps[p.by], Version: p.version, Pinned: pinned})
+			if !pinned {
+				continue
+			}
+		}
+		keep[i] = true
+		for key, v := range trees[i] {
+			if _, ok := provided[key]; !ok {
+				provided[key] = provider{by: i, version: v}
+			}
+		}
+	}
+
+	var roots []formatter.Dependency
+	for i, d := range deps {
+		if keep[i] {
+			roots = append(roots, d)
+		}
+	}
+	return roots, covered, nil
+}
+
+// collect records the resolved descendants of n by groupId:artifactId.
+func collect(n *Node, into map[string]string) {
+	for _, c := range n.Children {
+		if c.Omitted {
+			continue
+		}
+		into[c.Dependency.GroupID+":"+c.Dependency.ArtifactID] = c.Dependency.Version
+		collect(c, into)
+	}
+}
@@ -0,0 +1,291 @@
+// Package settings reads the parts of Maven's settings.xml that affect how
+// repositories are reached: mirrors, proxies, server credentials and the
+// repositories of active profiles.
+package settings
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CentralID is the repository id Maven uses for Maven Central.
+const CentralID = "central"
+
+type Settings struct {
+	LocalRepository string    `xml:"localRepository"`
+	Mirrors         []Mirror  `xml:"mirrors>mirror"`
+	Proxies         []Proxy   `xml:"proxies>proxy"`
+	Servers         []Server  `xml:"servers>server"`
+	Profiles        []Profile `xml:"profiles>profile"`
+	ActiveProfiles  []string  `xml:"activeProfiles>activeProfile"`
+}
+
+type Mirror struct {
+	ID       string `xml:"id"`
+	URL      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
+}
+
+type Proxy struct {
+	ID            string `xml:"id"`
+	Active        string `xml:"active"`
+	Protocol      string `xml:"protocol"`
+	Host          string `xml:"host"`
+	Port          string `xml:"port"`
+	Username      string `xml:"username"`
+	Password      string `xml:"password"`
+	NonProxyHosts string `xml:"nonProxyHosts"`
+}
+
+type Server struct {
+	ID            string `xml:"id"`
+	Username      string `xml:"username"`
+	Password      string `xml:"password"`
+	Configuration struct {
+		HTTPHeaders []struct {
+			Name  string `xml:"name"`
+			Value string `xml:"value"`
+		} `xml:"httpHeaders>property"`
+	} `xml:"configuration"`
+}
+
+type Profile struct {
+	ID         string `xml:"id"`
+	Activation struct {
+		ActiveByDefault bool `xml:"activeByDefault"`
+	} `xml:"activation"`
+	Repositories []Repository `xml:"repositories>repository"`
+}
+
+type Repository struct {
+	ID  string `xml:"id"`
+	URL string `xml:"url"`
+}
+
+// DefaultPath is where Maven looks for the user's settings.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "settings.xml")
+}
+
+// LocalRepositoryPath is the local repository Maven installs artifacts
+// into, ~/.m2/repository unless settings.xml moves it.
+func (s *Settings) LocalRepositoryPath() string {
+	if s.LocalRepository != "" {
+		return s.LocalRepository
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// Load parses the settings file at path. A missing file yields empty
+// settings, since most users never create one.
+func Load(path string) (*Settings, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) || path == "" {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var s Settings
+	if err := xml.NewDecoder(f).Decode(&s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.expand()
+	return &s, nil
+}
+
+// expand resolves ${env.NAME} and ${user.home} in the values that commonly
+// use them.
+func (s *Settings) expand() {
+	s.LocalRepository = expand(s.LocalRepository)
+	for i := range s.Mirrors {
+		s.Mirrors[i].URL = expand(s.Mirrors[i].URL)
+	}
+	for i := range s.Proxies {
+		p := &s.Proxies[i]
+		p.Host, p.Username, p.Password = expand(p.Host), expand(p.Username), expand(p.Password)
+	}
+	for i := range s.Servers {
+		srv := &s.Servers[i]
+		srv.Username, srv.Password = expand(srv.Username), expand(srv.Password)
+		for j := range srv.Configuration.HTTPHeaders {
+			srv.Configuration.HTTPHeaders[j].Value = expand(srv.Configuration.HTTPHeaders[j].Value)
+		}
+	}
+	for i := range s.Profiles {
+		for j := range s.Profiles[i].Repositories {
+			s.Profiles[i].Repositories[j].URL = expand(s.Profiles[i].Repositories[j].URL)
+		}
+	}
+}
+
+func expand(v string) string {
+	return os.Expand(strings.TrimSpace(v), func(name string) string {
+		switch {
+		case strings.HasPrefix(name, "env."):
+			return os.Getenv(strings.TrimPrefix(name, "env."))
+		case name == "user.home":
+			home, _ := os.UserHomeDir()
+			return home
+		}
+		return "${" + name + "}"
+	})
+}
+
+// Mirror returns the mirror that replaces the repository with the given id
+// and URL, following Maven's mirrorOf rules, or nil when it is reached
+// directly.
+func (s *Settings) Mirror(id, repoURL string) *Mirror {
+	// An exact id match beats any wildcard
+	for i, m := range s.Mirrors {
+		for _, pattern := range strings.Split(m.MirrorOf, ",") {
+			if strings.TrimSpace(pattern) == id {
+				return &s.Mirrors[i]
+			}
+		}
+	}
+	for i, m := range s.Mirrors {
+		if mirrorOf(m.MirrorOf, id, repoURL) {
+			return &s.Mirrors[i]
+		}
+	}
+	return nil
+}
+
+func mirrorOf(spec, id, repoURL string) bool {
+	matched := false
+	for _, pattern := range strings.Split(spec, ",") {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case pattern == "":
+		case strings.HasPrefix(pattern, "!"):
+			if pattern[1:] == id {
+				return false
+			}
+		case pattern == "*":
+			matched = true
+		case pattern == "external:*":
+			matched = matched || isExternal(repoURL)
+		case pattern == "external:http:*":
+			matched = matched || (isExternal(repoURL) && strings.HasPrefix(repoURL, "http:"))
+		case pattern == id:
+			matched = true
+		}
+	}
+	return matched
+}
+
+func isExternal(repoURL string) bool {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme == "file" {
+		return false
+	}
+	host := u.Hostname()
+	return host != "localhost" && host != "127.0.0.1"
+}
+
+// Server returns the credentials entry for a repository or mirror id.
+func (s *Settings) Server(id string) *Server {
+	for i, srv := range s.Servers {
+		if srv.ID == id {
+			return &s.Servers[i]
+		}
+	}
+	return nil
+}
+
+// Encrypted reports whether the password was encrypted with
+// mvn --encrypt-password, which mvns cannot decrypt.
+func (srv *Server) Encrypted() bool {
+	p := srv.Password
+	return strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") && len(p) > 2
+}
+
+// Headers returns the custom HTTP headers configured for the server.
+func (srv *Server) Headers() map[string]string {
+	if len(srv.Configuration.HTTPHeaders) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(srv.Configuration.HTTPHeaders))
+	for _, h := range srv.Configuration.HTTPHeaders {
+		headers[strings.TrimSpace(h.Name)] = h.Value
+	}
+	return headers
+}
+
+// Repositories lists the repositories declared by active profiles.
+func (s *Settings) Repositories() []Repository {
+	active := make(map[string]bool, len(s.ActiveProfiles))
+	for _, id := range s.ActiveProfiles {
+		active[strings.TrimSpace(id)] = true
+	}
+	var repos []Repository
+	for _, p := range s.Profiles {
+		if active[p.ID] || p.Activation.ActiveByDefault {
+			repos = append(repos, p.Repositories...)
+		}
+	}
+	return repos
+}
+
+// ProxyFunc picks the first active proxy, like Maven does, and honours its
+// nonProxyHosts. It is suitable for http.Transport.Proxy.
+func (s *Settings) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	var proxy *Proxy
+	for i, p := range s.Proxies {
+		if p.Active == "" || strings.EqualFold(p.Active, "true") {
+			proxy = &s.Proxies[i]
+			break
+		}
+	}
+	if proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	scheme := proxy.Protocol
+	if scheme == "" {
+		scheme = "http"
+	}
+	host := proxy.Host
+	if proxy.Port != "" {
+		host = net.JoinHostPort(proxy.Host, proxy.Port)
+	}
+	u := &url.URL{Scheme: scheme, Host: host}
+	if proxy.Username != "" {
+		u.User = url.UserPassword(proxy.Username, proxy.Password)
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(proxy.NonProxyHosts, req.URL.Hostname()) {
+			return nil, nil
+		}
+		return u, nil
+	}
+}
+
+// bypassProxy matches host against a |-separated nonProxyHosts list in
+// which * is a wildcard.
+func bypassProxy(nonProxyHosts, host string) bool {
+	for _, pattern := range strings.FieldsFunc(nonProxyHosts, func(r rune) bool { return r == '|' || r == ',' }) {
+		if ok, _ := filepath.Match(strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(host)); ok {
+			return true
+		}
+	}
+	return false
+}
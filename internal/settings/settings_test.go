@@ -0,0 +1,120 @@
+package settings
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSettings = `<settings>
+  <localRepository>${user.home}/m2cache</localRepository>
+  <mirrors>
+    <mirror><id>corp</id><url>https://nexus.corp/repository/public</url><mirrorOf>*,!snapshots</mirrorOf></mirror>
+    <mirror><id>special</id><url>https://special.corp/maven</url><mirrorOf>special</mirrorOf></mirror>
+  </mirrors>
+  <proxies>
+    <proxy><id>off</id><active>false</active><host>unused</host><port>1</port></proxy>
+    <proxy><id>corp</id><protocol>http</protocol><host>proxy.corp</host><port>3128</port>
+      <username>me</username><password>pw</password><nonProxyHosts>localhost|*.corp</nonProxyHosts></proxy>
+  </proxies>
+  <servers>
+    <server><id>corp</id><username>deployer</username><password>${env.MVNS_TEST_PASSWORD}</password></server>
+    <server><id>token</id><password>{COQLCE6DU6GtcS5P=}</password>
+      <configuration><httpHeaders><property><name>X-Api-Key</name><value>k</value></property></httpHeaders></configuration>
+    </server>
+  </servers>
+  <profiles>
+    <profile><id>extra</id><repositories><repository><id>snapshots</id><url>https://snap.corp/maven</url></repository></repositories></profile>
+    <profile><id>idle</id><repositories><repository><id>idle</id><url>https://idle.corp</url></repository></repositories></profile>
+  </profiles>
+  <activeProfiles><activeProfile>extra</activeProfile></activeProfiles>
+</settings>`
+
+func load(t *testing.T) *Settings {
+	t.Helper()
+	t.Setenv("MVNS_TEST_PASSWORD", "secret")
+	path := filepath.Join(t.TempDir(), "settings.xml")
+	if err := os.WriteFile(path, []byte(testSettings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return s
+}
+
+func TestMirror(t *testing.T) {
+	s := load(t)
+	tests := []struct {
+		id, url, want string
+	}{
+		{"central", "https://repo.maven.apache.org/maven2", "corp"},
+		{"special", "https://example.com", "special"},
+		{"snapshots", "https://snap.corp/maven", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if m := s.Mirror(tt.id, tt.url); m != nil {
+			got = m.ID
+		}
+		if got != tt.want {
+			t.Errorf("Mirror(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := load(t)
+	corp := s.Server("corp")
+	if corp == nil || corp.Password != "secret" || corp.Encrypted() {
+		t.Errorf("corp server = %+v, want expanded plain password", corp)
+	}
+	token := s.Server("token")
+	if token == nil || !token.Encrypted() || token.Headers()["X-Api-Key"] != "k" {
+		t.Errorf("token server = %+v, want encrypted password and header", token)
+	}
+	if s.Server("nope") != nil {
+		t.Error("unknown server should be nil")
+	}
+}
+
+func TestRepositories(t *testing.T) {
+	repos := load(t).Repositories()
+	if len(repos) != 1 || repos[0].ID != "snapshots" {
+		t.Errorf("Repositories() = %+v, want only the active profile's", repos)
+	}
+}
+
+func TestProxyFunc(t *testing.T) {
+	proxy := load(t).ProxyFunc()
+
+	req, _ := http.NewRequest("GET", "https://search.maven.org/solrsearch/select", nil)
+	u, err := proxy(req)
+	if err != nil || u == nil || u.String() != "http://me:pw@proxy.corp:3128" {
+		t.Errorf("proxy = %v, %v; want the active corp proxy", u, err)
+	}
+
+	req, _ = http.NewRequest("GET", "https://nexus.corp/repository/public", nil)
+	if u, _ := proxy(req); u != nil {
+		t.Errorf("proxy for non-proxy host = %v, want direct", u)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "settings.xml"))
+	if err != nil || s == nil {
+		t.Fatalf("Load of missing file = %v, %v; want empty settings", s, err)
+	}
+}
+
+func TestLocalRepositoryPath(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	if got, want := load(t).LocalRepositoryPath(), home+"/m2cache"; got != want {
+		t.Errorf("LocalRepositoryPath = %q, want %q", got, want)
+	}
+	if got, want := (&Settings{}).LocalRepositoryPath(), filepath.Join(home, ".m2", "repository"); got != want {
+		t.Errorf("default LocalRepositoryPath = %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,6 +11,9 @@ import (
 	"github.com/maher/mvns/internal/formatter"
 	"github.com/maher/mvns/internal/history"
 	"github.com/maher/mvns/internal/i18n"
+	"github.com/maher/mvns/internal/ranking"
+	"github.com/maher/mvns/internal/resolver"
+	"github.com/maher/mvns/internal/version"
 )
 
 type screen int
@@ -16,11 +21,13 @@ type screen int
 const (
 	screenSearch screen = iota
 	screenVersions
+	screenDetails
+	screenTree
 	screenSnippets
 )
 
 type App struct {
-	client     *api.Client
+	client     api.Searcher
 	locale     *i18n.Locale
 	theme      *Theme
 	formatters []formatter.Formatter
@@ -44,38 +51,100 @@ type App struct {
 	perPage      int
 	historyIdx   int
 	prefetchIdx  int
+	ranking      ranking.Strategy
+	// scalaVariants holds the cross-built variants of collapsed results,
+	// newest Scala version first
+	scalaVariants map[string][]api.Doc
+
+	// Request lifecycle: every search bumps searchGen and cancels the
+	// previous context; live search debounces keystrokes via liveSeq
+	searchCtx      context.Context
+	cancelSearch   context.CancelFunc
+	cancelPrefetch context.CancelFunc
+	cancelLoad     context.CancelFunc
+	cancelTree     context.CancelFunc
+	cancelDownload context.CancelFunc
+	cancelRefresh  context.CancelFunc
+	searchGen      int
+	lastQuery      string
+	liveSearch     bool
+	liveSeq        int
+
+	// Offline mode: answers come from the cache, cacheInfo tells how old
+	// the latest one was
+	offline   bool
+	cacheInfo api.CacheInfo
+	// changed marks rows that a background revalidation updated, by doc ID
+	changed map[string]bool
 
 	// Version screen
-	selectedDoc    api.Doc
-	versions       []api.Doc
-	stableVersions []api.Doc
-	preVersions    []api.Doc
-	versionCursor  int
+	selectedDoc   api.Doc
+	versions      []api.Doc
+	versionCursor int
+	// versionExpanded holds the open release lines; versionFilter narrows
+	// the list to matching versions
+	versionExpanded map[string]bool
+	versionFilter   textinput.Model
+	// rangeInput edits versionRange, which narrows the list to the
+	// versions a constraint allows
+	rangeInput   textinput.Model
+	rangeErr     string
+	versionRange *version.Constraint
 
-	// Snippet screen
+	// Details screen
 	selectedVersion api.Doc
+	pom             *api.POM
+	detailsLoading  bool
+	detailScroll    int
+
+	// Tree screen
+	resolver      *resolver.Resolver
+	treeRoot      *resolver.Node
+	treeLoading   bool
+	treeCursor    int
+	treeCollapsed map[*resolver.Node]bool
+	treeVerbose   bool
+
+	// Snippet screen
 	formatIdx       int
 	selectedScope   string
 	snippetCache    map[string]string
+	// scriptMode wraps script formats into a runnable skeleton
+	scriptMode      bool
+	download        *download
+	downloadDir     string
+	downloadRepo    string
 }
 
 type searchResultMsg struct {
-	resp *api.SearchResponse
-	err  error
+	gen   int
+	query string
+	resp  *api.SearchResponse
+	err   error
 }
 
 type versionResultMsg struct {
+	id   string
 	resp *api.SearchResponse
 	err  error
 }
 
-func NewApp(client *api.Client, locale *i18n.Locale, theme *Theme, hist *history.History) *App {
+func NewApp(client api.Searcher, locale *i18n.Locale, theme *Theme, hist *history.History) *App {
 	ti := textinput.New()
 	ti.Placeholder = locale.T("search.placeholder")
 	ti.Focus()
 	ti.Prompt = ""
 	ti.Width = 60
 
+	ri := textinput.New()
+	ri.Placeholder = "[1.2,2.0)  1.+  ^2.3  latest.release"
+	ri.Prompt = ""
+	ri.Width = 40
+
+	vf := textinput.New()
+	vf.Prompt = ""
+	vf.Width = 30
+
 	s := spinner.New()
 	s.Spinner = spinner.Pulse
 	s.Style = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
@@ -88,10 +157,14 @@ func NewApp(client *api.Client, locale *i18n.Locale, theme *Theme, hist *history
 		history:    hist,
 		screen:     screenSearch,
 		searchInput: ti,
+		rangeInput:  ri,
+		versionFilter: vf,
 		spinner:    s,
 		perPage:    20,
 		historyIdx: -1,
+		ranking:    ranking.Relevance,
 		snippetCache: make(map[string]string),
+		downloadDir:  ".",
 	}
 }
 
@@ -99,6 +172,43 @@ func (a *App) SetSearchValue(v string) {
 	a.searchInput.SetValue(v)
 }
 
+// SetRanking picks how results are ordered; "o" cycles through the rest.
+func (a *App) SetRanking(s ranking.Strategy) {
+	a.ranking = s
+}
+
+// SetDownloadDir sets where the snippet screen downloads files to.
+func (a *App) SetDownloadDir(dir string) {
+	a.downloadDir = dir
+}
+
+// SetDownloadRepository makes the snippet screen download into the Maven
+// repository layout below repo, such as ~/.m2/repository, instead.
+func (a *App) SetDownloadRepository(repo string) {
+	a.downloadRepo = repo
+}
+
+// SetLiveSearch turns on searching while typing.
+func (a *App) SetLiveSearch(on bool) {
+	a.liveSearch = on
+}
+
+// typing reports whether a text input has the keyboard.
+func (a *App) typing() bool {
+	return a.searchInput.Focused() || a.rangeInput.Focused() || a.versionFilter.Focused()
+}
+
+// renew cancels the request guarded by *cancel and returns a context for
+// its replacement.
+func renew(cancel *context.CancelFunc) context.Context {
+	if *cancel != nil {
+		(*cancel)()
+	}
+	ctx, c := context.WithCancel(context.Background())
+	*cancel = c
+	return ctx
+}
+
 func (a *App) Init() tea.Cmd {
 	if a.searchInput.Value() != "" {
 		return tea.Batch(textinput.Blink, a.spinner.Tick, a.doSearch())
@@ -113,6 +223,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
+		if a.offline {
+			// Status bar
+			a.height--
+		}
 		a.searchInput.Width = a.width - 10
 		if a.searchInput.Width > 60 {
 			a.searchInput.Width = 60
@@ -121,10 +235,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		k := msg.String()
 		if k == "ctrl+c" {
+			a.stopDownload()
 			return a, tea.Quit
 		}
-		// Global shortcut to jump to search
-		if !a.searchInput.Focused() && (k == "/" || k == "s") {
+		// Global shortcut to jump to search; "/" filters the versions screen
+		if !a.typing() && (k == "s" || k == "/" && a.screen != screenVersions) {
+			a.stopDownload()
 			a.screen = screenSearch
 			a.searchInput.Focus()
 			return a, nil
@@ -132,6 +248,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case spinner.TickMsg:
 		a.spinner, cmd = a.spinner.Update(msg)
 		return a, cmd
+	case downloadProgressMsg, downloadDoneMsg:
+		return a, a.updateDownload(msg)
 	}
 
 	switch a.screen {
@@ -139,6 +257,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a.updateSearch(msg)
 	case screenVersions:
 		return a.updateVersions(msg)
+	case screenDetails:
+		return a.updateDetails(msg)
+	case screenTree:
+		return a.updateTree(msg)
 	case screenSnippets:
 		return a.updateSnippets(msg)
 	}
@@ -147,13 +269,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (a *App) View() string {
+	var view string
 	switch a.screen {
 	case screenSearch:
-		return a.viewSearch()
+		view = a.viewSearch()
 	case screenVersions:
-		return a.viewVersions()
+		view = a.viewVersions()
+	case screenDetails:
+		view = a.viewDetails()
+	case screenTree:
+		view = a.viewTree()
 	case screenSnippets:
-		return a.viewSnippets()
+		view = a.viewSnippets()
+	}
+	if bar := a.statusBar(); bar != "" {
+		view += "\n" + bar
 	}
-	return ""
+	return view
 }
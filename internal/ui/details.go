@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maher/mvns/internal/api"
+)
+
+type pomResultMsg struct {
+	id  string
+	pom *api.POM
+	err error
+}
+
+func (a *App) fetchDetails() tea.Cmd {
+	a.pom = nil
+	a.detailScroll = 0
+	a.statusMsg = ""
+
+	fetcher, ok := a.client.(api.POMFetcher)
+	if !ok {
+		a.statusMsg = a.locale.T("details.unavailable")
+		return nil
+	}
+
+	v := a.selectedVersion
+	a.detailsLoading = true
+	ctx := renew(&a.cancelLoad)
+	return func() tea.Msg {
+		pom, err := fetcher.POM(ctx, v.GroupID, v.ArtifactID, v.Version, false)
+		return pomResultMsg{id: v.ID, pom: pom, err: err}
+	}
+}
+
+func (a *App) updateDetails(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pomResultMsg:
+		if msg.id != a.selectedVersion.ID || errors.Is(msg.err, context.Canceled) {
+			return a, nil
+		}
+		a.detailsLoading = false
+		if msg.err != nil {
+			a.err = msg.err
+			a.statusMsg = a.locale.T("details.unavailable")
+			return a, nil
+		}
+		a.pom = msg.pom
+		a.cacheInfo = msg.pom.CacheInfo
+		return a, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			a.screen = screenVersions
+			a.statusMsg = ""
+			return a, nil
+		case "enter":
+			a.screen = screenSnippets
+			a.formatIdx = 0
+			a.selectedScope = a.selectedVersion.DetectScope()
+			a.statusMsg = ""
+			return a, nil
+		case "t":
+			a.screen = screenTree
+			return a, a.fetchTree()
+		case "up", "k":
+			if a.detailScroll > 0 {
+				a.detailScroll--
+			}
+		case "down", "j":
+			if a.pom != nil && a.detailScroll < len(a.pom.Dependencies)-1 {
+				a.detailScroll++
+			}
+		}
+	}
+
+	return a, nil
+}
+
+func (a *App) viewDetails() string {
+	var b strings.Builder
+
+	v := a.selectedVersion
+	name := fmt.Sprintf("%s:%s:%s", v.GroupID, v.ArtifactID, v.Version)
+	title := a.theme.Title.Render(name)
+	if a.detailsLoading {
+		title = fmt.Sprintf("%s %s %s", title, a.spinner.View(), a.theme.Dimmed.Render(a.locale.T("details.loading")))
+	}
+	b.WriteString("  " + title + "\n\n")
+
+	if a.statusMsg != "" {
+		b.WriteString("  " + a.theme.Error.Render(a.statusMsg) + "\n\n")
+	}
+
+	lines := 0
+	if pom := a.pom; pom != nil {
+		valueWidth := a.width - 18
+		if valueWidth < 20 {
+			valueWidth = 20
+		}
+		field := func(key, value string) {
+			if value == "" {
+				return
+			}
+			label := a.theme.Dimmed.Render(fmt.Sprintf("  %-14s", a.locale.T(key)))
+			wrapped := lipgloss.NewStyle().Width(valueWidth).Render(value)
+			for i, line := range strings.Split(wrapped, "\n") {
+				if i > 0 {
+					label = strings.Repeat(" ", 16)
+				}
+				b.WriteString(label + a.theme.Normal.Render(line) + "\n")
+				lines++
+			}
+		}
+
+		field("details.name", pom.Name)
+		field("details.description", pom.Description)
+		field("details.url", pom.URL)
+		var licenses []string
+		for _, l := range pom.Licenses {
+			licenses = append(licenses, strings.TrimSpace(l.Name))
+		}
+		field("details.licenses", strings.Join(licenses, ", "))
+		if pom.SCM != nil {
+			scm := pom.SCM.URL
+			if scm == "" {
+				scm = pom.SCM.Connection
+			}
+			field("details.scm", scm)
+		}
+		var devs []string
+		for _, d := range pom.Developers {
+			dev := d.Name
+			if dev == "" {
+				dev = d.ID
+			}
+			if d.Organization != "" && d.Organization != dev {
+				dev += " (" + d.Organization + ")"
+			}
+			devs = append(devs, dev)
+		}
+		field("details.developers", strings.Join(devs, ", "))
+		if p := pom.Parent; p != nil {
+			field("details.parent", p.GroupID+":"+p.ArtifactID+":"+p.Version)
+		}
+
+		b.WriteString("\n  " + a.theme.Subtitle.Render(fmt.Sprintf(a.locale.T("details.dependencies"), len(pom.Dependencies))) + "\n")
+		b.WriteString("  " + a.theme.Separator.Render(strings.Repeat("─", 40)) + "\n")
+
+		// Title, fields, headers and help take the rest of the screen
+		available := a.height - lines - 10
+		if available < 1 {
+			available = 1
+		}
+		deps := pom.Dependencies
+		start := a.detailScroll
+		if start > len(deps) {
+			start = len(deps)
+		}
+		end := start + available
+		if end > len(deps) {
+			end = len(deps)
+		}
+		for _, d := range deps[start:end] {
+			version := d.Version
+			if version == "" {
+				version = a.locale.T("details.managed")
+			}
+			var tags []string
+			if d.Scope != "" && d.Scope != "compile" {
+				tags = append(tags, d.Scope)
+			}
+			if d.IsOptional() {
+				tags = append(tags, "optional")
+			}
+			line := fmt.Sprintf("  %-55s %s", d.GroupID+":"+d.ArtifactID, version)
+			b.WriteString(a.theme.Normal.Render(line))
+			if len(tags) > 0 {
+				b.WriteString("  " + a.theme.Dimmed.Render(strings.Join(tags, ", ")))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n  " + a.theme.Help.Render(a.locale.T("details.help")))
+
+	return b.String()
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maher/mvns/internal/api"
+)
+
+// download is the file being fetched from the snippet screen. Leaving the
+// screen or quitting cancels it.
+type download struct {
+	name        string
+	done, total int64
+	running     bool
+	path, alg   string
+	err         error
+	updates     chan tea.Msg
+}
+
+type downloadProgressMsg struct {
+	dl          *download
+	done, total int64
+}
+
+type downloadDoneMsg struct {
+	dl        *download
+	path, alg string
+	err       error
+}
+
+// fileExtension maps a packaging to the extension of its main file.
+func fileExtension(packaging string) string {
+	switch packaging {
+	case "", "bundle", "maven-plugin", "eclipse-plugin", "hk2-jar":
+		return "jar"
+	}
+	return packaging
+}
+
+func (a *App) startDownload() tea.Cmd {
+	if a.download != nil && a.download.running {
+		return nil
+	}
+	d, ok := a.client.(api.Downloader)
+	if !ok {
+		a.download = &download{err: api.ErrUnsupported}
+		return nil
+	}
+	v := a.selectedVersion
+	f := api.ArtifactFile{
+		GroupID:    v.GroupID,
+		ArtifactID: v.ArtifactID,
+		Version:    v.Version,
+		Extension:  fileExtension(v.Packaging),
+	}
+	dl := &download{name: f.Name(), total: -1, running: true, updates: make(chan tea.Msg, 1)}
+	a.download = dl
+	a.statusMsg = ""
+
+	ctx := renew(&a.cancelDownload)
+	dir := a.downloadDir
+	if a.downloadRepo != "" {
+		dir = filepath.Join(a.downloadRepo, filepath.FromSlash(path.Dir(f.Path())))
+	}
+	go func() {
+		path, alg, err := api.DownloadFile(ctx, d, f, dir, func(done, total int64) {
+			// Skip updates while the UI is still drawing the last one
+			select {
+			case dl.updates <- downloadProgressMsg{dl: dl, done: done, total: total}:
+			default:
+			}
+		})
+		dl.updates <- downloadDoneMsg{dl: dl, path: path, alg: alg, err: err}
+	}()
+	return dl.next()
+}
+
+// stopDownload cancels a running download, which removes its partial
+// file, and forgets it.
+func (a *App) stopDownload() {
+	if a.download != nil && a.download.running {
+		a.cancelDownload()
+		a.download = nil
+	}
+}
+
+// next waits for the download's next update.
+func (dl *download) next() tea.Cmd {
+	return func() tea.Msg {
+		return <-dl.updates
+	}
+}
+
+// updateDownload applies download updates. Updates of a stopped download
+// are dropped, which also ends its chain of next commands.
+func (a *App) updateDownload(msg tea.Msg) tea.Cmd {
+	dl := a.download
+	switch msg := msg.(type) {
+	case downloadProgressMsg:
+		if msg.dl != dl {
+			return nil
+		}
+		dl.done, dl.total = msg.done, msg.total
+		return dl.next()
+	case downloadDoneMsg:
+		if msg.dl != dl {
+			return nil
+		}
+		dl.running = false
+		dl.path, dl.alg, dl.err = msg.path, msg.alg, msg.err
+	}
+	return nil
+}
+
+// viewDownload renders the progress bar, or how the download ended.
+func (a *App) viewDownload() string {
+	dl := a.download
+	switch {
+	case dl == nil:
+		return ""
+	case dl.err != nil:
+		return a.theme.Error.Render(ErrorMessage(a.locale, dl.err))
+	case !dl.running:
+		return a.theme.Success.Render(fmt.Sprintf(a.locale.T("download.saved"), dl.path, dl.alg))
+	}
+
+	line := a.theme.Dimmed.Render(dl.name) + "  "
+	if dl.total <= 0 {
+		return line + a.theme.Normal.Render(FormatBytes(dl.done))
+	}
+	const width = 30
+	done := min(dl.done, dl.total)
+	filled := int(int64(width) * done / dl.total)
+	bar := a.theme.Success.Render(strings.Repeat("█", filled)) + a.theme.Dimmed.Render(strings.Repeat("░", width-filled))
+	return line + bar + a.theme.Normal.Render(fmt.Sprintf(" %3d%%  %s / %s", 100*done/dl.total, FormatBytes(dl.done), FormatBytes(dl.total)))
+}
+
+// FormatBytes renders a size in B, KB or MB.
+func FormatBytes(n int64) string {
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%d B", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
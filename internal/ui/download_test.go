@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+type downloadClient struct {
+	stubClient
+	got api.ArtifactFile
+}
+
+func (d *downloadClient) Download(ctx context.Context, f api.ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	d.got = f
+	progress(3, 6)
+	_, err := w.Write([]byte("jar123"))
+	progress(6, 6)
+	return "SHA1", err
+}
+
+func TestSnippetDownload(t *testing.T) {
+	client := &downloadClient{}
+	app := newTestApp(t, client)
+	app.searchInput.Blur()
+	dir := t.TempDir()
+	app.SetDownloadDir(dir)
+	app.screen = screenSnippets
+	app.selectedVersion = api.Doc{GroupID: "g", ArtifactID: "a", Version: "1.0", Packaging: "bundle"}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	for cmd != nil {
+		_, cmd = app.Update(cmd())
+	}
+
+	if client.got.Extension != "jar" {
+		t.Errorf("bundle downloaded as %q, want jar", client.got.Extension)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "a-1.0.jar"))
+	if err != nil || string(data) != "jar123" {
+		t.Fatalf("downloaded file = %q, %v", data, err)
+	}
+	if app.download.running || !strings.Contains(app.viewDownload(), "SHA1") {
+		t.Errorf("download should be reported as verified: %q", app.viewDownload())
+	}
+}
+
+func TestSnippetDownloadIntoRepository(t *testing.T) {
+	client := &downloadClient{}
+	app := newTestApp(t, client)
+	app.searchInput.Blur()
+	repo := t.TempDir()
+	app.SetDownloadRepository(repo)
+	app.screen = screenSnippets
+	app.selectedVersion = api.Doc{GroupID: "org.example", ArtifactID: "a", Version: "1.0", Packaging: "jar"}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	for cmd != nil {
+		_, cmd = app.Update(cmd())
+	}
+	if _, err := os.Stat(filepath.Join(repo, "org", "example", "a", "1.0", "a-1.0.jar")); err != nil {
+		t.Errorf("jar not in the repository layout: %v", err)
+	}
+}
+
+// stallingClient downloads until the context ends.
+type stallingClient struct {
+	stubClient
+}
+
+func (s *stallingClient) Download(ctx context.Context, f api.ArtifactFile, w io.Writer, progress func(done, total int64)) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestLeavingSnippetsCancelsDownload(t *testing.T) {
+	app := newTestApp(t, &stallingClient{})
+	app.searchInput.Blur()
+	dir := t.TempDir()
+	app.SetDownloadDir(dir)
+	app.screen = screenSnippets
+	app.selectedVersion = api.Doc{GroupID: "g", ArtifactID: "a", Version: "1.0"}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	// The canceled download reports back once and ends its chain
+	if _, cmd = app.Update(cmd()); cmd != nil {
+		t.Error("a canceled download should not be followed any further")
+	}
+	if app.download != nil {
+		t.Errorf("download = %+v, want it forgotten", app.download)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("partial files left behind: %v", files)
+	}
+}
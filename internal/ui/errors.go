@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/i18n"
+)
+
+// ErrorMessage turns an API error into the most specific localized message.
+func ErrorMessage(locale *i18n.Locale, err error) string {
+	var (
+		se *api.SyntaxError
+		ce *api.ChecksumError
+		pe *fs.PathError
+	)
+	switch {
+	case errors.As(err, &se):
+		return fmt.Sprintf(locale.T("error.syntax"), se.Msg)
+	case errors.As(err, &ce):
+		return fmt.Sprintf(locale.T("error.checksum"), ce.File, ce.Algorithm)
+	case errors.Is(err, api.ErrNoChecksum):
+		return locale.T("error.nochecksum")
+	case errors.As(err, &pe):
+		return fmt.Sprintf(locale.T("error.file"), pe.Path, pe.Err)
+	case errors.Is(err, api.ErrUnsupported):
+		return locale.T("error.unsupported")
+	case errors.Is(err, api.ErrNotCached):
+		return locale.T("error.offline")
+	case errors.Is(err, api.ErrRateLimited):
+		if d := api.RetryAfter(err); d > 0 {
+			return fmt.Sprintf(locale.T("error.ratelimited.retry"), int(d.Round(time.Second)/time.Second))
+		}
+		return locale.T("error.ratelimited")
+	case errors.Is(err, api.ErrNotFound):
+		return locale.T("error.notfound")
+	case errors.Is(err, api.ErrUnavailable):
+		return locale.T("error.unavailable")
+	}
+	return locale.T("error.network")
+}
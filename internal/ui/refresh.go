@@ -0,0 +1,82 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maher/mvns/internal/api"
+)
+
+// Stale responses from the cache are shown straight away and fetched again
+// in the background; the refresh messages below carry the fresh answer.
+
+type searchRefreshMsg struct {
+	gen   int
+	query string
+	resp  *api.SearchResponse
+}
+
+type versionRefreshMsg struct {
+	id   string
+	resp *api.SearchResponse
+}
+
+func (a *App) needsRefresh(resp *api.SearchResponse) bool {
+	return resp != nil && resp.Stale && !a.offline
+}
+
+func (a *App) refreshSearch(gen int, query string) tea.Cmd {
+	// Bound to the current search, so a new query drops the refresh
+	ctx := a.searchCtx
+	perPage, start := a.perPage, a.page*a.perPage
+	return func() tea.Msg {
+		resp, err := a.search(ctx, query, perPage, start, true)
+		if err != nil {
+			// Keep showing the stale results
+			return nil
+		}
+		return searchRefreshMsg{gen: gen, query: query, resp: resp}
+	}
+}
+
+func (a *App) refreshVersions(doc api.Doc) tea.Cmd {
+	ctx := renew(&a.cancelRefresh)
+	return func() tea.Msg {
+		resp, err := a.client.Versions(ctx, doc.GroupID, doc.ArtifactID, versionPageRows, true)
+		if err != nil {
+			return nil
+		}
+		return versionRefreshMsg{id: doc.ID, resp: resp}
+	}
+}
+
+// markChanged records which of docs are new or differ from before.
+func (a *App) markChanged(before, docs []api.Doc) {
+	old := make(map[string]api.Doc, len(before))
+	for _, d := range before {
+		old[d.ID] = d
+	}
+	a.changed = make(map[string]bool)
+	for _, d := range docs {
+		if prev, ok := old[d.ID]; !ok || !sameListing(prev, d) {
+			a.changed[d.ID] = true
+		}
+	}
+}
+
+// sameListing compares the fields a result row shows.
+func sameListing(a, b api.Doc) bool {
+	return a.LatestVersion == b.LatestVersion && a.Version == b.Version &&
+		a.VersionCount == b.VersionCount && a.Timestamp == b.Timestamp && a.Packaging == b.Packaging
+}
+
+// cursorOn returns the index of the doc with id, or fallback.
+func cursorOn(docs []api.Doc, id string, fallback int) int {
+	for i, d := range docs {
+		if d.ID == id {
+			return i
+		}
+	}
+	if fallback >= len(docs) {
+		return max(len(docs)-1, 0)
+	}
+	return fallback
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/formatter"
+	"github.com/maher/mvns/internal/version"
+)
+
+// scalaKey identifies the cross-built variants of one Scala artifact, or
+// is empty for Java artifacts.
+func scalaKey(d api.Doc) string {
+	base, scala := formatter.ScalaSuffix(d.ArtifactID)
+	if scala == "" {
+		return ""
+	}
+	return d.GroupID + ":" + base
+}
+
+// collapseScala keeps one entry per cross-built artifact, the build for
+// the newest Scala version, in place of the first variant found. The
+// others are kept in a.scalaVariants so "x" can switch between them.
+func (a *App) collapseScala(docs []api.Doc) []api.Doc {
+	a.scalaVariants = make(map[string][]api.Doc)
+	var out []api.Doc
+	for _, d := range docs {
+		key := scalaKey(d)
+		if key == "" {
+			out = append(out, d)
+			continue
+		}
+		if _, ok := a.scalaVariants[key]; !ok {
+			out = append(out, d)
+		}
+		a.scalaVariants[key] = append(a.scalaVariants[key], d)
+	}
+	for key, variants := range a.scalaVariants {
+		sort.SliceStable(variants, func(i, j int) bool {
+			_, si := formatter.ScalaSuffix(variants[i].ArtifactID)
+			_, sj := formatter.ScalaSuffix(variants[j].ArtifactID)
+			return version.Compare(si, sj) > 0
+		})
+		if len(variants) == 1 {
+			delete(a.scalaVariants, key)
+		}
+	}
+	for i, d := range out {
+		if variants := a.scalaVariants[scalaKey(d)]; len(variants) > 1 {
+			out[i] = variants[0]
+		}
+	}
+	return out
+}
+
+// cycleScala switches the selected result to the next Scala version it is
+// built for.
+func (a *App) cycleScala() {
+	if a.resultCursor >= len(a.results) {
+		return
+	}
+	cur := a.results[a.resultCursor]
+	variants := a.scalaVariants[scalaKey(cur)]
+	for i, v := range variants {
+		if v.ID == cur.ID {
+			a.results[a.resultCursor] = variants[(i+1)%len(variants)]
+			return
+		}
+	}
+}
+
+// scalaVersions lists the Scala versions of a collapsed result, the shown
+// one in brackets, such as "[3] 2.13 2.12".
+func (a *App) scalaVersions(d api.Doc) string {
+	variants := a.scalaVariants[scalaKey(d)]
+	if len(variants) < 2 {
+		return ""
+	}
+	parts := make([]string, len(variants))
+	for i, v := range variants {
+		_, scala := formatter.ScalaSuffix(v.ArtifactID)
+		if v.ID == d.ID {
+			scala = "[" + scala + "]"
+		}
+		parts[i] = scala
+	}
+	return strings.Join(parts, " ")
+}
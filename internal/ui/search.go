@@ -1,67 +1,142 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/maher/mvns/internal/api"
 )
 
+// liveSearchDelay is how long typing must pause before a live search starts.
+const liveSearchDelay = 300 * time.Millisecond
+
+type liveSearchMsg struct {
+	seq int
+}
+
 func (a *App) doSearch() tea.Cmd {
 	query := strings.TrimSpace(a.searchInput.Value())
-	if query == "" {
+	if query == "" || !a.checkQuery() {
 		return nil
 	}
 
 	a.history.Add(query)
 	a.history.Save()
 	a.historyIdx = -1
-	a.searching = true
 	a.results = nil
 	a.totalResults = 0
 
-	start := a.page * a.perPage
-
-	return func() tea.Msg {
-		// Use Multimodal search for concurrent execution of specific queries
-		resp, err := a.client.SearchMultimodal(query, a.perPage, start, false)
-		return searchResultMsg{resp: resp, err: err}
-	}
+	return a.startSearch(query, false)
 }
 
 func (a *App) doRefresh() tea.Cmd {
 	query := strings.TrimSpace(a.searchInput.Value())
-	if query == "" {
+	if query == "" || !a.checkQuery() {
 		return nil
 	}
 
-	a.searching = true
 	a.results = nil
 	a.totalResults = 0
-	
-	start := a.page * a.perPage
+
+	return a.startSearch(query, true)
+}
+
+// startSearch cancels whatever search is still in flight and tags the new
+// one with a generation, so a late response can never replace newer results.
+func (a *App) startSearch(query string, bypassCache bool) tea.Cmd {
+	ctx := renew(&a.cancelSearch)
+	a.searchCtx = ctx
+	a.searchGen++
+	a.searching = true
+	a.lastQuery = query
+
+	gen := a.searchGen
+	perPage := a.perPage
+	start := a.page * perPage
 
 	return func() tea.Msg {
-		resp, err := a.client.SearchMultimodal(query, a.perPage, start, true)
-		return searchResultMsg{resp: resp, err: err}
+		resp, err := a.search(ctx, query, perPage, start, bypassCache)
+		return searchResultMsg{gen: gen, query: query, resp: resp, err: err}
+	}
+}
+
+// checkQuery parses the search box and, instead of searching, reports a
+// syntax error inline. The search still in flight is dropped.
+func (a *App) checkQuery() bool {
+	value := a.searchInput.Value()
+	if _, ok := api.ParseClassQuery(value); ok {
+		return true
+	}
+	if _, err := api.ParseQuery(value); err != nil {
+		renew(&a.cancelSearch)
+		a.searchGen++
+		a.searching = false
+		a.err = err
+		a.statusMsg = ErrorMessage(a.locale, err)
+		return false
+	}
+	return true
+}
+
+// syntaxCaret points at where the query in the search box stopped parsing.
+func (a *App) syntaxCaret(indent int) string {
+	var se *api.SyntaxError
+	if !errors.As(a.err, &se) {
+		return ""
+	}
+	value := a.searchInput.Value()
+	pos := se.Pos
+	if pos > len(value) {
+		pos = len(value)
 	}
+	return strings.Repeat(" ", indent+utf8.RuneCountInString(value[:pos])) + a.theme.Error.Render("^")
+}
+
+// search runs what the user typed: a class search for "class:" queries and
+// a multimodal search, with its concurrent sub-queries, otherwise.
+func (a *App) search(ctx context.Context, query string, rows, start int, bypassCache bool) (*api.SearchResponse, error) {
+	if class, ok := api.ParseClassQuery(query); ok {
+		cs, ok := a.client.(api.ClassSearcher)
+		if !ok {
+			return nil, api.ErrUnsupported
+		}
+		return cs.SearchClass(ctx, class, rows, start, bypassCache)
+	}
+	return a.client.SearchMultimodal(ctx, query, rows, start, bypassCache)
+}
+
+// debounceSearch schedules a live search; only the last keystroke's tick
+// survives the sequence check.
+func (a *App) debounceSearch() tea.Cmd {
+	a.liveSeq++
+	seq := a.liveSeq
+	return tea.Tick(liveSearchDelay, func(time.Time) tea.Msg {
+		return liveSearchMsg{seq: seq}
+	})
 }
 
 func (a *App) prefetchNextPages(query string) tea.Cmd {
+	// Bound to the current search, so a new query stops the prefetch
+	ctx := a.searchCtx
+	page, perPage, total := a.page, a.perPage, a.totalResults
+
 	return func() tea.Msg {
 		// Fetch next 2 pages in the background
 		for p := 1; p <= 2; p++ {
-			targetPage := a.page + p
-			if targetPage*a.perPage >= a.totalResults {
+			start := (page + p) * perPage
+			if start >= total {
+				break
+			}
+			// The client cache keeps the result for when the user pages
+			if _, err := a.search(ctx, query, perPage, start, false); err != nil {
 				break
 			}
-			start := targetPage * a.perPage
-			// Just fire and forget, the client cache will handle the storage
-			go a.client.SearchMultimodal(query, a.perPage, start, false)
 		}
 		return nil
 	}
@@ -82,6 +157,20 @@ func (a *App) prefetch() tea.Cmd {
 	})
 }
 
+// setResults deduplicates and ranks one page of docs for query. The client
+// already returns contiguous pages, so ranking only reorders within a page.
+func (a *App) setResults(docs []api.Doc, query string) {
+	uniqueResults := make([]api.Doc, 0, len(docs))
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		if !seen[doc.ID] {
+			seen[doc.ID] = true
+			uniqueResults = append(uniqueResults, doc)
+		}
+	}
+	a.results = a.ranking.Sort(a.collapseScala(uniqueResults), query)
+}
+
 func (a *App) findSuggestion() {
 	input := a.searchInput.Value()
 	if input == "" {
@@ -104,71 +193,51 @@ func (a *App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case prefetchMsg:
 		if !a.searchInput.Focused() && msg.cursor == a.resultCursor {
 			doc := a.results[a.resultCursor]
+			ctx := renew(&a.cancelPrefetch)
 			return a, func() tea.Msg {
-				_, _ = a.client.Versions(doc.GroupID, doc.ArtifactID, 200, false)
+				_, _ = a.client.Versions(ctx, doc.GroupID, doc.ArtifactID, versionPageRows, false)
 				return nil
 			}
 		}
 		return a, nil
 
+	case liveSearchMsg:
+		if msg.seq != a.liveSeq || !a.liveSearch {
+			return a, nil
+		}
+		query := strings.TrimSpace(a.searchInput.Value())
+		if query == "" {
+			renew(&a.cancelSearch)
+			a.searchGen++
+			a.searching = false
+			a.lastQuery = ""
+			a.results = nil
+			a.totalResults = 0
+			a.statusMsg = ""
+			return a, nil
+		}
+		if query == a.lastQuery || !a.checkQuery() {
+			return a, nil
+		}
+		a.page = 0
+		a.resultCursor = 0
+		return a, a.startSearch(query, false)
+
 	case searchResultMsg:
+		if msg.gen != a.searchGen {
+			// Superseded by a newer search
+			return a, nil
+		}
 		a.searching = false
 		if msg.err != nil {
 			a.err = msg.err
-			a.statusMsg = a.locale.T("error.network")
+			a.statusMsg = ErrorMessage(a.locale, msg.err)
 			return a, nil
 		}
-		a.results = msg.resp.Response.Docs
-		// Deduplicate results by ID
-		uniqueResults := make([]api.Doc, 0, len(a.results))
-		seen := make(map[string]bool)
-		for _, doc := range a.results {
-			if !seen[doc.ID] {
-				seen[doc.ID] = true
-				uniqueResults = append(uniqueResults, doc)
-			}
-		}
-		a.results = uniqueResults
-
-		// Sort results: 
-		// 1. Exact ArtifactID match
-		// 2. GroupID contains query (official package proxy)
-		// 3. VersionCount (popularity)
-		// 4. Timestamp (recency)
-		query := strings.TrimSpace(a.searchInput.Value())
-		sort.Slice(a.results, func(i, j int) bool {
-			// Tier 1: Exact artifactId match
-			iExact := a.results[i].ArtifactID == query
-			jExact := a.results[j].ArtifactID == query
-			if iExact != jExact {
-				return iExact
-			}
-
-			// Tier 2: GroupID contains query (e.g. org.junit.jupiter contains junit-jupiter)
-			// We normalize dots to hyphens to catch matches like org.junit.jupiter vs junit-jupiter
-			iNormalizedGroup := strings.ReplaceAll(strings.ToLower(a.results[i].GroupID), ".", "-")
-			jNormalizedGroup := strings.ReplaceAll(strings.ToLower(a.results[j].GroupID), ".", "-")
-			lowerQuery := strings.ToLower(query)
-
-			iGroupMatch := strings.Contains(iNormalizedGroup, lowerQuery)
-			jGroupMatch := strings.Contains(jNormalizedGroup, lowerQuery)
-			if iGroupMatch != jGroupMatch {
-				return iGroupMatch
-			}
-
-			// Tier 3: Version count as popularity proxy
-			if a.results[i].VersionCount != a.results[j].VersionCount {
-				return a.results[i].VersionCount > a.results[j].VersionCount
-			}
-
-			// Tier 4: Timestamp as recency
-			return a.results[i].Timestamp > a.results[j].Timestamp
-		})
-
-		// Limit to perPage for display
-		if len(a.results) > a.perPage {
-			a.results = a.results[:a.perPage]
-		}
+		a.noteCache(msg.resp)
+		a.changed = nil
+		a.setResults(msg.resp.Response.Docs, msg.query)
+		query := msg.query
 
 		a.totalResults = msg.resp.Response.NumFound
 		a.resultCursor = 0
@@ -186,8 +255,31 @@ func (a *App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, a.prefetchNextPages(query))
 		}
 
+		if a.needsRefresh(msg.resp) {
+			cmds = append(cmds, a.refreshSearch(msg.gen, query))
+		}
+
 		return a, tea.Batch(cmds...)
 
+	case searchRefreshMsg:
+		if msg.gen != a.searchGen {
+			return a, nil
+		}
+		before := a.results
+		selected := ""
+		if a.resultCursor < len(before) {
+			selected = before[a.resultCursor].ID
+		}
+		a.noteCache(msg.resp)
+		a.setResults(msg.resp.Response.Docs, msg.query)
+		a.totalResults = msg.resp.Response.NumFound
+		a.resultCursor = cursorOn(a.results, selected, a.resultCursor)
+		a.markChanged(before, a.results)
+		if len(a.results) > 0 && a.statusMsg == a.locale.T("error.noresults") {
+			a.statusMsg = ""
+		}
+		return a, nil
+
 	case tea.KeyMsg:
 		k := msg.String()
 
@@ -232,6 +324,12 @@ func (a *App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "ctrl+r":
 			return a, a.doRefresh()
+		case "ctrl+l":
+			a.liveSearch = !a.liveSearch
+			if a.liveSearch && a.searchInput.Focused() {
+				return a, a.debounceSearch()
+			}
+			return a, nil
 		case "enter":
 			if a.searchInput.Focused() {
 				a.searchInput.Blur()
@@ -296,6 +394,17 @@ func (a *App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case "/":
 				a.searchInput.Focus()
+			case "o":
+				selected := ""
+				if a.resultCursor < len(a.results) {
+					selected = a.results[a.resultCursor].ID
+				}
+				a.ranking = a.ranking.Next()
+				a.results = a.ranking.Sort(a.results, a.lastQuery)
+				a.resultCursor = cursorOn(a.results, selected, a.resultCursor)
+			case "x":
+				a.cycleScala()
+				return a, a.prefetch()
 			case "n":
 				maxPage := (a.totalResults - 1) / a.perPage
 				if a.page < maxPage {
@@ -317,6 +426,15 @@ func (a *App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	a.searchInput, cmd = a.searchInput.Update(msg)
 	if a.searchInput.Value() != oldVal {
 		a.findSuggestion()
+		var se *api.SyntaxError
+		if errors.As(a.err, &se) {
+			// The caret no longer points at the right place
+			a.err = nil
+			a.statusMsg = ""
+		}
+		if a.liveSearch {
+			return a, tea.Batch(cmd, a.debounceSearch())
+		}
 	}
 	return a, cmd
 }
@@ -325,6 +443,9 @@ func (a *App) viewSearch() string {
 	var b strings.Builder
 
 	title := a.theme.Title.Render(a.locale.T("search.title"))
+	if a.liveSearch {
+		title += " " + a.theme.Dimmed.Render(a.locale.T("search.live"))
+	}
 	if a.searching {
 		title = fmt.Sprintf("%s %s %s", title, a.spinner.View(), a.theme.Dimmed.Render(a.locale.T("search.searching")))
 	}
@@ -348,7 +469,8 @@ func (a *App) viewSearch() string {
 	if a.searchInput.Focused() && a.suggestion != "" {
 		line += a.theme.Dimmed.Render(a.suggestion)
 	}
-	b.WriteString(line + "\n\n")
+	b.WriteString(line + "\n")
+	b.WriteString(a.syntaxCaret(2+lipgloss.Width(label)) + "\n")
 
 	if a.statusMsg != "" {
 		b.WriteString("  " + a.theme.Error.Render(a.statusMsg) + "\n\n")
@@ -398,6 +520,9 @@ func (a *App) viewSearch() string {
 		}
 
 		versionCountStr := fmt.Sprintf(a.locale.T("results.versionCount"), doc.VersionCount)
+		if scala := a.scalaVersions(doc); scala != "" {
+			versionCountStr += " | " + fmt.Sprintf(a.locale.T("results.scala"), scala)
+		}
 
 		var line1, line2 string
 		if i == a.resultCursor && !a.searchInput.Focused() {
@@ -406,10 +531,22 @@ func (a *App) viewSearch() string {
 				selectedStyle = selectedStyle.Width(a.width - 2)
 			}
 			line1 = selectedStyle.Render(fmt.Sprintf("> %-55s %s", name, "v"+version))
+			if len(doc.Classes) > 0 {
+				versionCountStr = classList(doc.Classes)
+			}
 			line2 = selectedStyle.Render(fmt.Sprintf("  %s | %s | %s", doc.Time().Format("2006-01-02"), doc.Packaging, versionCountStr))
 		} else {
-			line1 = "  " + a.theme.Normal.Render(fmt.Sprintf("%-55s %s", name, "v"+version))
-			line2 = "  " + a.theme.Dimmed.Render(fmt.Sprintf("%s | %s | %s", doc.Time().Format("2006-01-02"), doc.Packaging, versionCountStr))
+			marker := "  "
+			if a.changed[doc.ID] {
+				// Updated by a background refresh
+				marker = a.theme.Success.Render("• ")
+			}
+			line1 = marker + a.theme.Normal.Render(fmt.Sprintf("%-55s %s", name, "v"+version))
+			if len(doc.Classes) > 0 {
+				line2 = "  " + a.theme.Dimmed.Render(fmt.Sprintf("%s | %s | ", doc.Time().Format("2006-01-02"), doc.Packaging)) + a.highlightClasses(doc.Classes)
+			} else {
+				line2 = "  " + a.theme.Dimmed.Render(fmt.Sprintf("%s | %s | %s", doc.Time().Format("2006-01-02"), doc.Packaging, versionCountStr))
+			}
 		}
 
 		b.WriteString(line1 + "\n")
@@ -422,7 +559,8 @@ func (a *App) viewSearch() string {
 	if a.totalResults > 0 {
 		totalPages := (a.totalResults-1)/a.perPage + 1
 		pageInfo := fmt.Sprintf(a.locale.T("results.page"), a.page+1, totalPages)
-		itemsInfo := fmt.Sprintf(a.locale.T("results.itemsCount"), len(a.results))
+		itemsInfo := fmt.Sprintf(a.locale.T("results.itemsCount"), len(a.results)) + " | " +
+			fmt.Sprintf(a.locale.T("results.rank"), a.locale.T("rank."+a.ranking.Key))
 		
 		// Calculate spacing for right alignment
 		padding := a.width - lipgloss.Width(pageInfo) - lipgloss.Width(itemsInfo) - 4
@@ -442,3 +580,42 @@ func (a *App) viewSearch() string {
 
 	return b.String()
 }
+
+// maxClasses bounds how many matching class names a result row lists.
+const maxClasses = 2
+
+func classList(classes []string) string {
+	if len(classes) <= maxClasses {
+		return strings.Join(classes, ", ")
+	}
+	return fmt.Sprintf("%s +%d", strings.Join(classes[:maxClasses], ", "), len(classes)-maxClasses)
+}
+
+// highlightClasses renders the class names of a class search with the
+// searched name emphasised.
+func (a *App) highlightClasses(classes []string) string {
+	term, _ := api.ParseClassQuery(a.lastQuery)
+	if i := strings.LastIndex(term, "."); i >= 0 {
+		term = term[i+1:]
+	}
+
+	shown := classes
+	if len(shown) > maxClasses {
+		shown = shown[:maxClasses]
+	}
+	parts := make([]string, len(shown))
+	for i, c := range shown {
+		parts[i] = a.theme.Dimmed.Render(c)
+		if term == "" {
+			continue
+		}
+		if at := strings.LastIndex(strings.ToLower(c), strings.ToLower(term)); at >= 0 {
+			parts[i] = a.theme.Dimmed.Render(c[:at]) + a.theme.Success.Render(c[at:at+len(term)]) + a.theme.Dimmed.Render(c[at+len(term):])
+		}
+	}
+	out := strings.Join(parts, a.theme.Dimmed.Render(", "))
+	if len(classes) > maxClasses {
+		out += a.theme.Dimmed.Render(fmt.Sprintf(" +%d", len(classes)-maxClasses))
+	}
+	return out
+}
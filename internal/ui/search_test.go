@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/history"
+	"github.com/maher/mvns/internal/i18n"
+	"github.com/maher/mvns/internal/ranking"
+	"github.com/maher/mvns/locales"
+)
+
+type stubClient struct {
+	queries []string
+}
+
+func (s *stubClient) Search(ctx context.Context, query string, rows, start int, bypassCache bool) (*api.SearchResponse, error) {
+	return s.SearchMultimodal(ctx, query, rows, start, bypassCache)
+}
+
+func (s *stubClient) SearchMultimodal(ctx context.Context, query string, rows, start int, bypassCache bool) (*api.SearchResponse, error) {
+	s.queries = append(s.queries, query)
+	return &api.SearchResponse{Response: api.ResponseBody{
+		NumFound: 1,
+		Docs:     []api.Doc{{ID: "g:" + query, GroupID: "g", ArtifactID: query}},
+	}}, nil
+}
+
+func (s *stubClient) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*api.SearchResponse, error) {
+	return &api.SearchResponse{}, nil
+}
+
+func newTestApp(t *testing.T, client api.Searcher) *App {
+	t.Helper()
+	locale, err := i18n.NewFromFS(locales.FS, ".", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist, _ := history.New(filepath.Join(t.TempDir(), "history.json"))
+	return NewApp(client, locale, NewTheme("dark"), hist)
+}
+
+func TestStaleSearchResultDropped(t *testing.T) {
+	client := &stubClient{}
+	app := newTestApp(t, client)
+
+	app.SetSearchValue("guava")
+	first := app.doSearch()
+	app.SetSearchValue("guice")
+	second := app.doSearch()
+
+	app.Update(second())
+	app.Update(first())
+
+	if len(app.results) != 1 || app.results[0].ArtifactID != "guice" {
+		t.Errorf("results = %+v, want only the newer guice result", app.results)
+	}
+	if app.searching {
+		t.Error("searching should be false after the current response")
+	}
+}
+
+func TestLiveSearchDebounce(t *testing.T) {
+	client := &stubClient{}
+	app := newTestApp(t, client)
+	app.SetLiveSearch(true)
+
+	app.SetSearchValue("gui")
+	app.debounceSearch()
+	app.SetSearchValue("guice")
+	app.debounceSearch()
+
+	// Only the tick of the last keystroke triggers a search
+	_, cmd := app.Update(liveSearchMsg{seq: 1})
+	if cmd != nil {
+		t.Error("superseded tick should not search")
+	}
+	_, cmd = app.Update(liveSearchMsg{seq: 2})
+	if cmd == nil {
+		t.Fatal("latest tick should search")
+	}
+	app.Update(cmd())
+
+	if len(client.queries) != 1 || client.queries[0] != "guice" {
+		t.Errorf("queries = %v, want [guice]", client.queries)
+	}
+	if !app.searchInput.Focused() {
+		t.Error("live search must keep the input focused")
+	}
+	if len(app.history.List()) != 0 {
+		t.Errorf("history = %v, live search must not record history", app.history.List())
+	}
+}
+
+func TestStaleResultsRefreshInPlace(t *testing.T) {
+	app := newTestApp(t, &stubClient{})
+	app.SetSearchValue("guice")
+	app.doSearch()
+
+	stale := &api.SearchResponse{
+		Response:  api.ResponseBody{NumFound: 2, Docs: []api.Doc{{ID: "a", ArtifactID: "a", VersionCount: 1}, {ID: "b", ArtifactID: "b"}}},
+		CacheInfo: api.CacheInfo{Stale: true},
+	}
+	_, cmd := app.Update(searchResultMsg{gen: app.searchGen, query: "guice", resp: stale})
+	if cmd == nil {
+		t.Fatal("stale results should schedule a refresh")
+	}
+
+	fresh := &api.SearchResponse{Response: api.ResponseBody{NumFound: 2, Docs: []api.Doc{{ID: "a", ArtifactID: "a", VersionCount: 2}, {ID: "b", ArtifactID: "b"}}}}
+	app.resultCursor = 1
+	app.Update(searchRefreshMsg{gen: app.searchGen, query: "guice", resp: fresh})
+
+	if !app.changed["a"] || app.changed["b"] {
+		t.Errorf("changed = %v, want only a", app.changed)
+	}
+	if app.results[app.resultCursor].ID != "b" {
+		t.Errorf("cursor moved to %q, want it to stay on b", app.results[app.resultCursor].ID)
+	}
+}
+
+func TestSyntaxErrorShownInline(t *testing.T) {
+	client := &stubClient{}
+	app := newTestApp(t, client)
+
+	app.SetSearchValue(`guice after:soon`)
+	if cmd := app.doSearch(); cmd != nil {
+		t.Fatal("invalid query should not search")
+	}
+	if len(client.queries) != 0 {
+		t.Errorf("queries = %v, want none", client.queries)
+	}
+	if !strings.HasPrefix(app.statusMsg, "Invalid query:") {
+		t.Errorf("statusMsg = %q, want a syntax error", app.statusMsg)
+	}
+	if !strings.Contains(app.View(), "^") {
+		t.Error("view should point at the error")
+	}
+}
+
+func TestCycleRankingKeepsSelection(t *testing.T) {
+	app := newTestApp(t, &stubClient{})
+	app.searchInput.Blur()
+	app.setResults([]api.Doc{
+		{ID: "b:beta", GroupID: "b", ArtifactID: "beta", VersionCount: 9},
+		{ID: "a:alpha", GroupID: "a", ArtifactID: "alpha", VersionCount: 1},
+	}, "x")
+	app.resultCursor = 1
+	selected := app.results[1].ID
+
+	for _, want := range []string{"recency", "popularity", "alphabetical"} {
+		app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+		if app.ranking.Key != want {
+			t.Fatalf("ranking = %s, want %s", app.ranking.Key, want)
+		}
+		if app.results[app.resultCursor].ID != selected {
+			t.Errorf("%s: cursor moved off %s", want, selected)
+		}
+	}
+	if app.results[0].ID != "a:alpha" {
+		t.Errorf("alphabetical order starts with %s", app.results[0].ID)
+	}
+}
+
+func TestScalaVariantsCollapsed(t *testing.T) {
+	app := newTestApp(t, &stubClient{})
+	app.searchInput.Blur()
+	app.SetRanking(ranking.Alphabetical)
+	app.setResults([]api.Doc{
+		{ID: "org.typelevel:cats-core_2.12", GroupID: "org.typelevel", ArtifactID: "cats-core_2.12"},
+		{ID: "org.typelevel:cats-core_3", GroupID: "org.typelevel", ArtifactID: "cats-core_3"},
+		{ID: "org.typelevel:cats-core_2.13", GroupID: "org.typelevel", ArtifactID: "cats-core_2.13"},
+		{ID: "org.typelevel:cats-kernel_2.13", GroupID: "org.typelevel", ArtifactID: "cats-kernel_2.13"},
+	}, "cats")
+
+	if len(app.results) != 2 || app.results[0].ArtifactID != "cats-core_3" {
+		t.Fatalf("results = %+v, want cats-core_3 and cats-kernel_2.13", app.results)
+	}
+	if got := app.scalaVersions(app.results[0]); got != "[3] 2.13 2.12" {
+		t.Errorf("scala versions = %q", got)
+	}
+	if got := app.scalaVersions(app.results[1]); got != "" {
+		t.Errorf("a single build should have no selector, got %q", got)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if got := app.results[0].ArtifactID; got != "cats-core_2.13" {
+		t.Errorf("after x the result is %s, want cats-core_2.13", got)
+	}
+}
@@ -62,7 +62,8 @@ func (a *App) updateSnippets(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
-			a.screen = screenVersions
+			a.stopDownload()
+			a.screen = screenDetails
 			a.statusMsg = ""
 			return a, nil
 		case "tab", "right", "l":
@@ -81,6 +82,15 @@ func (a *App) updateSnippets(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			a.statusMsg = ""
+		case "w":
+			if _, ok := a.formatters[a.formatIdx].(formatter.ScriptFormatter); ok {
+				a.scriptMode = !a.scriptMode
+				a.statusMsg = ""
+			} else {
+				a.statusMsg = a.locale.T("snippets.noscript")
+			}
+		case "d":
+			return a, a.startDownload()
 		case "enter":
 			snippet := a.currentSnippet()
 			return a, func() tea.Msg {
@@ -100,7 +110,11 @@ func (a *App) currentSnippet() string {
 		Version:    a.selectedVersion.Version,
 		Scope:      a.selectedScope,
 	}
-	return a.formatters[a.formatIdx].Format(dep)
+	f := a.formatters[a.formatIdx]
+	if sf, ok := f.(formatter.ScriptFormatter); ok && a.scriptMode {
+		return sf.Script([]formatter.Dependency{dep})
+	}
+	return f.Format(dep)
 }
 
 func (a *App) viewSnippets() string {
@@ -125,10 +139,19 @@ func (a *App) viewSnippets() string {
 	if scopeName == "" {
 		scopeName = "compile"
 	}
-	b.WriteString("  " + a.theme.Dimmed.Render("Scope: ") + a.theme.Normal.Render(scopeName) + " (press 'c' to change)\n\n")
+	b.WriteString("  " + a.theme.Dimmed.Render("Scope: ") + a.theme.Normal.Render(scopeName) + " (press 'c' to change)\n")
+	_, script := f.(formatter.ScriptFormatter)
+	if script {
+		mode := a.locale.T("snippets.script.off")
+		if a.scriptMode {
+			mode = a.locale.T("snippets.script.on")
+		}
+		b.WriteString("  " + a.theme.Dimmed.Render(a.locale.T("snippets.script")) + a.theme.Normal.Render(mode) + "\n")
+	}
+	b.WriteString("\n")
 
-	// Cache lookup - include scope in key
-	cacheKey := fmt.Sprintf("%s:%s:%s:%s:%s", a.selectedVersion.ID, a.selectedVersion.Version, f.Name(), a.theme.Name, a.selectedScope)
+	// Cache lookup - include scope and script mode in key
+	cacheKey := fmt.Sprintf("%s:%s:%s:%s:%s:%t", a.selectedVersion.ID, a.selectedVersion.Version, f.Name(), a.theme.Name, a.selectedScope, script && a.scriptMode)
 	highlighted, ok := a.snippetCache[cacheKey]
 	if !ok {
 		snippet := a.currentSnippet()
@@ -143,6 +166,9 @@ func (a *App) viewSnippets() string {
 	if a.statusMsg != "" {
 		b.WriteString("\n  " + a.theme.Success.Render(a.statusMsg))
 	}
+	if dl := a.viewDownload(); dl != "" {
+		b.WriteString("\n  " + dl)
+	}
 
 	b.WriteString("\n\n  " + a.theme.Help.Render(a.locale.T("snippets.help")))
 
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/formatter"
+)
+
+func TestSnippetScriptSkeleton(t *testing.T) {
+	app := newTestApp(t, &stubClient{})
+	app.searchInput.Blur()
+	app.screen = screenSnippets
+	app.selectedVersion = api.Doc{GroupID: "info.picocli", ArtifactID: "picocli", Version: "4.7.5"}
+	w := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")}
+
+	// Maven has no skeleton
+	app.Update(w)
+	if app.scriptMode || app.statusMsg == "" {
+		t.Errorf("w on Maven: scriptMode %v, status %q", app.scriptMode, app.statusMsg)
+	}
+
+	for i, f := range app.formatters {
+		if _, ok := f.(*formatter.JBang); ok {
+			app.formatIdx = i
+		}
+	}
+	if got := app.currentSnippet(); got != "//DEPS info.picocli:picocli:4.7.5" {
+		t.Errorf("snippet = %q", got)
+	}
+	app.Update(w)
+	if got := app.currentSnippet(); !strings.HasPrefix(got, "///usr/bin/env jbang") || !strings.Contains(got, "//DEPS info.picocli:picocli:4.7.5") {
+		t.Errorf("script = %q", got)
+	}
+	app.Update(w)
+	if got := app.currentSnippet(); got != "//DEPS info.picocli:picocli:4.7.5" {
+		t.Errorf("snippet after toggling back = %q", got)
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+// SetOffline shows the offline status bar; it reserves one terminal line.
+func (a *App) SetOffline(on bool) {
+	a.offline = on
+}
+
+// noteCache remembers the cache state of the latest answer for the status bar.
+func (a *App) noteCache(resp *api.SearchResponse) {
+	if resp != nil {
+		a.cacheInfo = resp.CacheInfo
+	}
+}
+
+func (a *App) statusBar() string {
+	if !a.offline {
+		return ""
+	}
+	bar := a.theme.Error.Render(a.locale.T("status.offline"))
+	if a.cacheInfo.Age > 0 {
+		bar += "  " + a.theme.Dimmed.Render(fmt.Sprintf(a.locale.T("status.cached"), formatAge(a.cacheInfo.Age)))
+	}
+	if a.cacheInfo.Stale {
+		bar += "  " + a.theme.Error.Render(a.locale.T("status.stale"))
+	}
+	return "  " + bar
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
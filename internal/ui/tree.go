@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/formatter"
+	"github.com/maher/mvns/internal/resolver"
+)
+
+type treeResultMsg struct {
+	id   string
+	root *resolver.Node
+	err  error
+}
+
+type treeRow struct {
+	node   *resolver.Node
+	parent int
+	prefix string
+}
+
+func (a *App) fetchTree() tea.Cmd {
+	a.treeRoot = nil
+	a.treeCursor = 0
+	a.treeCollapsed = make(map[*resolver.Node]bool)
+	a.statusMsg = ""
+
+	fetcher, ok := a.client.(api.POMFetcher)
+	if !ok {
+		a.statusMsg = a.locale.T("details.unavailable")
+		return nil
+	}
+	if a.resolver == nil {
+		a.resolver = resolver.New(fetcher)
+	}
+
+	v := a.selectedVersion
+	dep := formatter.Dependency{
+		GroupID:    v.GroupID,
+		ArtifactID: v.ArtifactID,
+		Version:    v.Version,
+	}
+	a.treeLoading = true
+	r := a.resolver
+	// Separate from cancelLoad so the details POM keeps loading
+	ctx := renew(&a.cancelTree)
+	return func() tea.Msg {
+		root, err := r.Resolve(ctx, dep)
+		return treeResultMsg{id: v.ID, root: root, err: err}
+	}
+}
+
+// treeRows flattens the expanded part of the tree into display rows.
+func (a *App) treeRows() []treeRow {
+	if a.treeRoot == nil {
+		return nil
+	}
+	rows := []treeRow{{node: a.treeRoot, parent: -1}}
+	var walk func(n *resolver.Node, parent int, indent string)
+	walk = func(n *resolver.Node, parent int, indent string) {
+		if a.treeCollapsed[n] {
+			return
+		}
+		var children []*resolver.Node
+		for _, c := range n.Children {
+			if c.Omitted && !a.treeVerbose {
+				continue
+			}
+			children = append(children, c)
+		}
+		for i, c := range children {
+			branch, next := "├─ ", "│  "
+			if i == len(children)-1 {
+				branch, next = "└─ ", "   "
+			}
+			rows = append(rows, treeRow{node: c, parent: parent, prefix: indent + branch})
+			walk(c, len(rows)-1, indent+next)
+		}
+	}
+	walk(a.treeRoot, 0, "")
+	return rows
+}
+
+func (a *App) updateTree(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case treeResultMsg:
+		if msg.id != a.selectedVersion.ID || errors.Is(msg.err, context.Canceled) {
+			return a, nil
+		}
+		a.treeLoading = false
+		if msg.err != nil {
+			a.err = msg.err
+			a.statusMsg = a.locale.T("tree.failed")
+			return a, nil
+		}
+		a.treeRoot = msg.root
+		return a, nil
+
+	case tea.KeyMsg:
+		rows := a.treeRows()
+		switch msg.String() {
+		case "esc":
+			if a.treeLoading {
+				a.cancelTree()
+				a.treeLoading = false
+			}
+			a.screen = screenDetails
+			a.statusMsg = ""
+			return a, nil
+		case "up", "k":
+			if a.treeCursor > 0 {
+				a.treeCursor--
+			}
+		case "down", "j":
+			if a.treeCursor < len(rows)-1 {
+				a.treeCursor++
+			}
+		case "enter", " ":
+			if a.treeCursor < len(rows) {
+				n := rows[a.treeCursor].node
+				if len(n.Children) > 0 {
+					a.treeCollapsed[n] = !a.treeCollapsed[n]
+				}
+			}
+		case "right", "l":
+			if a.treeCursor < len(rows) {
+				delete(a.treeCollapsed, rows[a.treeCursor].node)
+			}
+		case "left", "h":
+			if a.treeCursor < len(rows) {
+				row := rows[a.treeCursor]
+				if len(row.node.Children) > 0 && !a.treeCollapsed[row.node] {
+					a.treeCollapsed[row.node] = true
+				} else if row.parent >= 0 {
+					a.treeCursor = row.parent
+				}
+			}
+		case "v":
+			a.treeVerbose = !a.treeVerbose
+			a.treeCursor = 0
+		}
+	}
+
+	return a, nil
+}
+
+func (a *App) viewTree() string {
+	var b strings.Builder
+
+	v := a.selectedVersion
+	name := fmt.Sprintf("%s:%s:%s", v.GroupID, v.ArtifactID, v.Version)
+	title := a.theme.Title.Render(name)
+	if a.treeLoading {
+		title = fmt.Sprintf("%s %s %s", title, a.spinner.View(), a.theme.Dimmed.Render(a.locale.T("tree.loading")))
+	}
+	b.WriteString("  " + title + "\n\n")
+
+	if a.statusMsg != "" {
+		b.WriteString("  " + a.theme.Error.Render(a.statusMsg) + "\n\n")
+	}
+
+	rows := a.treeRows()
+	if len(rows) > 0 {
+		b.WriteString("  " + a.theme.Subtitle.Render(fmt.Sprintf(a.locale.T("tree.summary"), a.treeRoot.Count())) + "\n")
+		b.WriteString("  " + a.theme.Separator.Render(strings.Repeat("─", 40)) + "\n")
+
+		// Title(2) + summary(2) + help(2)
+		available := a.height - 6
+		if available < 1 {
+			available = 1
+		}
+		start := 0
+		if len(rows) > available {
+			start = a.treeCursor - available/2
+			if start < 0 {
+				start = 0
+			}
+			if start+available > len(rows) {
+				start = len(rows) - available
+			}
+		}
+		end := start + available
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		for i := start; i < end; i++ {
+			row := rows[i]
+			marker := "  "
+			if len(row.node.Children) > 0 {
+				marker = "▾ "
+				if a.treeCollapsed[row.node] {
+					marker = "▸ "
+				}
+			}
+			line := row.prefix + marker + row.node.Label()
+			note := row.node.Note()
+			if a.treeCollapsed[row.node] {
+				note = strings.TrimSpace(fmt.Sprintf(a.locale.T("tree.hidden"), row.node.Count()) + " " + note)
+			}
+
+			switch {
+			case i == a.treeCursor:
+				if note != "" {
+					line += "  " + note
+				}
+				b.WriteString("  " + a.theme.Selected.Render(line) + "\n")
+			case row.node.Omitted || row.node.Err != nil:
+				b.WriteString("  " + a.theme.Dimmed.Render(line+"  "+note) + "\n")
+			default:
+				b.WriteString("  " + a.theme.Normal.Render(line))
+				if note != "" {
+					b.WriteString("  " + a.theme.Dimmed.Render(note))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	b.WriteString("\n  " + a.theme.Help.Render(a.locale.T("tree.help")))
+
+	return b.String()
+}
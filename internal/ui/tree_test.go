@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+type pomClient struct {
+	stubClient
+}
+
+func (p *pomClient) POM(ctx context.Context, groupID, artifactID, version string, bypassCache bool) (*api.POM, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &api.POM{ArtifactID: artifactID}, nil
+}
+
+func TestTreeDoesNotCancelDetails(t *testing.T) {
+	app := newTestApp(t, &pomClient{})
+	app.screen = screenDetails
+	app.selectedVersion = api.Doc{ID: "g:a:1", GroupID: "g", ArtifactID: "a", Version: "1"}
+
+	details := app.fetchDetails()
+	app.fetchTree()
+	app.Update(details())
+	if app.detailsLoading || app.pom == nil {
+		t.Errorf("details still loading after opening the tree: loading %v, pom %v", app.detailsLoading, app.pom)
+	}
+}
@@ -1,65 +1,159 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/maher/mvns/internal/api"
+	"github.com/maher/mvns/internal/version"
 )
 
+// otherLine groups versions that do not start with a number.
+const otherLine = "other"
+
+// versionRow is one line of the versions screen: a release line (major or
+// major.minor) or, at depth 2, a single version.
+type versionRow struct {
+	line  string
+	depth int
+	// doc is the version, or the latest release of the line.
+	doc   api.Doc
+	count int
+}
+
+func (r versionRow) key() string {
+	if r.line != "" {
+		return "line:" + r.line
+	}
+	return r.doc.ID
+}
+
+// versionPageRows is one request's worth of versions, the server's row
+// limit. Prefetching and refreshing stop there; only opening the versions
+// screen pages through the whole history.
+const versionPageRows = 200
+
 func (a *App) fetchVersions() tea.Cmd {
 	g := a.selectedDoc.GroupID
 	ar := a.selectedDoc.ArtifactID
 
+	id := a.selectedDoc.ID
+	ctx := renew(&a.cancelLoad)
+	a.versionRange = nil
+	a.versionFilter.SetValue("")
+
 	return func() tea.Msg {
-		resp, err := a.client.Versions(g, ar, 200, false)
-		return versionResultMsg{resp: resp, err: err}
+		resp, err := a.client.Versions(ctx, g, ar, 0, false)
+		return versionResultMsg{id: id, resp: resp, err: err}
 	}
 }
 
 func (a *App) updateVersions(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case versionResultMsg:
+		if msg.id != a.selectedDoc.ID || errors.Is(msg.err, context.Canceled) {
+			return a, nil
+		}
 		if msg.err != nil {
 			a.err = msg.err
-			a.statusMsg = a.locale.T("error.network")
+			a.statusMsg = ErrorMessage(a.locale, msg.err)
 			return a, nil
 		}
-		a.versions = msg.resp.Response.Docs
-		a.stableVersions = nil
-		a.preVersions = nil
-		for _, v := range a.versions {
-			if v.IsPreRelease() {
-				a.preVersions = append(a.preVersions, v)
-			} else {
-				a.stableVersions = append(a.stableVersions, v)
-			}
-		}
+		a.noteCache(msg.resp)
+		a.changed = nil
+		a.setVersions(msg.resp.Response.Docs)
+		a.expandNewestLine()
 		a.versionCursor = 0
 		a.statusMsg = ""
+		if a.needsRefresh(msg.resp) {
+			return a, a.refreshVersions(a.selectedDoc)
+		}
+		return a, nil
+
+	case versionRefreshMsg:
+		if msg.id != a.selectedDoc.ID {
+			return a, nil
+		}
+		before := a.allVersionsSorted()
+		selected := a.selectedRowKey()
+		a.noteCache(msg.resp)
+		docs := msg.resp.Response.Docs
+		if len(docs) >= versionPageRows {
+			// A full page holds the newest versions; older ones do not change
+			fresh := make(map[string]bool, len(docs))
+			for _, d := range docs {
+				fresh[d.ID] = true
+			}
+			docs = slices.Clone(docs)
+			for _, d := range a.versions {
+				if !fresh[d.ID] {
+					docs = append(docs, d)
+				}
+			}
+		}
+		a.setVersions(docs)
+		a.cursorOnRow(selected)
+		a.markChanged(before, a.allVersionsSorted())
 		return a, nil
 
 	case tea.KeyMsg:
-		allVersions := a.allVersionsSorted()
+		if a.rangeInput.Focused() {
+			return a.updateRange(msg)
+		}
+		if a.versionFilter.Focused() {
+			return a.updateVersionFilter(msg)
+		}
+		rows := a.versionRows()
 		switch msg.String() {
 		case "esc":
+			if a.versionFilter.Value() != "" {
+				a.versionFilter.SetValue("")
+				a.versionCursor = 0
+				return a, nil
+			}
 			a.screen = screenSearch
 			return a, nil
+		case "/":
+			a.versionFilter.CursorEnd()
+			return a, a.versionFilter.Focus()
+		case "r":
+			if a.versionRange != nil {
+				a.rangeInput.SetValue(a.versionRange.String())
+			} else {
+				a.rangeInput.SetValue("")
+			}
+			a.rangeInput.CursorEnd()
+			a.statusMsg = ""
+			return a, a.rangeInput.Focus()
 		case "enter":
-			if len(allVersions) > 0 {
-				a.selectedVersion = allVersions[a.versionCursor]
-				a.screen = screenSnippets
-				a.formatIdx = 0
-				a.selectedScope = a.selectedVersion.DetectScope()
+			if a.versionCursor >= len(rows) {
 				return a, nil
 			}
+			row := rows[a.versionCursor]
+			if row.line != "" {
+				a.setExpanded(row.line, !a.expanded(row.line))
+				return a, nil
+			}
+			a.selectedVersion = row.doc
+			a.screen = screenDetails
+			return a, a.fetchDetails()
+		case "right", "l":
+			if a.versionCursor < len(rows) && rows[a.versionCursor].line != "" {
+				a.setExpanded(rows[a.versionCursor].line, true)
+			}
+		case "left", "h":
+			a.collapseAtCursor(rows)
 		case "up", "k":
 			if a.versionCursor > 0 {
 				a.versionCursor--
 			}
 		case "down", "j":
-			if a.versionCursor < len(allVersions)-1 {
+			if a.versionCursor < len(rows)-1 {
 				a.versionCursor++
 			}
 		}
@@ -68,11 +162,268 @@ func (a *App) updateVersions(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// updateVersionFilter edits the in-list filter, narrowing the list as the
+// user types. Enter keeps the filter, Esc drops it.
+func (a *App) updateVersionFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		a.versionFilter.SetValue("")
+		a.versionFilter.Blur()
+		a.versionCursor = 0
+		return a, nil
+	case "enter", "down":
+		a.versionFilter.Blur()
+		return a, nil
+	}
+	var cmd tea.Cmd
+	old := a.versionFilter.Value()
+	a.versionFilter, cmd = a.versionFilter.Update(msg)
+	if a.versionFilter.Value() != old {
+		a.versionCursor = 0
+	}
+	return a, cmd
+}
+
+// updateRange edits the version constraint. Enter applies it, an empty one
+// shows all versions again, Esc leaves it unchanged.
+func (a *App) updateRange(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		a.rangeInput.Blur()
+		a.rangeErr = ""
+		return a, nil
+	case "enter":
+		raw := strings.TrimSpace(a.rangeInput.Value())
+		if raw == "" {
+			a.versionRange = nil
+		} else {
+			c, err := version.ParseConstraint(raw)
+			if err != nil {
+				a.rangeErr = err.Error()
+				return a, nil
+			}
+			a.versionRange = &c
+		}
+		a.rangeInput.Blur()
+		a.rangeErr = ""
+		a.versionCursor = 0
+		// Start on the version the constraint picks
+		if resolved, ok := a.resolvedVersion(); ok {
+			for i, row := range a.versionRows() {
+				if row.line == "" && row.doc.Version == resolved {
+					a.versionCursor = i
+				}
+			}
+		}
+		return a, nil
+	}
+	var cmd tea.Cmd
+	a.rangeInput, cmd = a.rangeInput.Update(msg)
+	return a, cmd
+}
+
+// setVersions keeps docs newest first by Maven's version order rather than
+// by publication date.
+func (a *App) setVersions(docs []api.Doc) {
+	a.versions = append([]api.Doc(nil), docs...)
+	sort.SliceStable(a.versions, func(i, j int) bool {
+		return version.Compare(a.versions[i].Version, a.versions[j].Version) > 0
+	})
+}
+
+// expandNewestLine opens the newest major line and its newest minor line,
+// leaving older lines collapsed.
+func (a *App) expandNewestLine() {
+	a.versionExpanded = make(map[string]bool)
+	for _, v := range a.versions {
+		if v.IsPreRelease() {
+			continue
+		}
+		major, minor := releaseLines(v.Version)
+		a.versionExpanded[major] = true
+		a.versionExpanded[minor] = true
+		return
+	}
+	if len(a.versions) > 0 {
+		major, minor := releaseLines(a.versions[0].Version)
+		a.versionExpanded[major] = true
+		a.versionExpanded[minor] = true
+	}
+}
+
+func releaseLines(v string) (major, minor string) {
+	major, ok := version.Line(v, 1)
+	if !ok {
+		return otherLine, otherLine
+	}
+	minor, _ = version.Line(v, 2)
+	return major, minor
+}
+
+// expanded reports whether a line shows its children. While a filter or
+// range narrows the list every line is open, so matches are never hidden.
+func (a *App) expanded(line string) bool {
+	if a.versionFilter.Value() != "" || a.versionRange != nil {
+		return true
+	}
+	return a.versionExpanded[line]
+}
+
+func (a *App) setExpanded(line string, open bool) {
+	if a.versionExpanded == nil {
+		a.versionExpanded = make(map[string]bool)
+	}
+	a.versionExpanded[line] = open
+}
+
+// collapseAtCursor closes the line under the cursor, or the line the
+// version under the cursor belongs to, and moves onto it.
+func (a *App) collapseAtCursor(rows []versionRow) {
+	if a.versionCursor >= len(rows) {
+		return
+	}
+	row := rows[a.versionCursor]
+	if row.line != "" && a.expanded(row.line) {
+		a.setExpanded(row.line, false)
+		return
+	}
+	// Walk up to the parent line
+	for i := a.versionCursor - 1; i >= 0; i-- {
+		if rows[i].line != "" && rows[i].depth < row.depth {
+			a.setExpanded(rows[i].line, false)
+			a.versionCursor = i
+			return
+		}
+	}
+}
+
+// matchesFilter reports whether v matches the in-list filter: a version
+// prefix such as "2.15", or any part of the version for other text.
+func (a *App) matchesFilter(v string) bool {
+	f := strings.ToLower(strings.TrimSpace(a.versionFilter.Value()))
+	switch {
+	case f == "":
+		return true
+	case f[0] >= '0' && f[0] <= '9':
+		return strings.HasPrefix(strings.ToLower(v), f)
+	}
+	return strings.Contains(strings.ToLower(v), f)
+}
+
+// resolvedVersion is what the range picks among all versions.
+func (a *App) resolvedVersion() (string, bool) {
+	if a.versionRange == nil {
+		return "", false
+	}
+	versions := make([]string, len(a.versions))
+	for i, v := range a.versions {
+		versions[i] = v.Version
+	}
+	return a.versionRange.Resolve(versions)
+}
+
+// allVersionsSorted lists the versions the filter and range allow, newest
+// first.
 func (a *App) allVersionsSorted() []api.Doc {
-	var all []api.Doc
-	all = append(all, a.stableVersions...)
-	all = append(all, a.preVersions...)
-	return all
+	var out []api.Doc
+	for _, v := range a.versions {
+		if a.versionRange != nil && !a.versionRange.Allows(v.Version) {
+			continue
+		}
+		if !a.matchesFilter(v.Version) {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// versionRows groups the visible versions by major and major.minor line.
+func (a *App) versionRows() []versionRow {
+	type group struct {
+		row      versionRow
+		children []*group
+		docs     []api.Doc
+	}
+	var (
+		majors []*group
+		lines  = make(map[string]*group)
+	)
+	add := func(parent *[]*group, line string, depth int) *group {
+		g, ok := lines[line]
+		if !ok {
+			g = &group{row: versionRow{line: line, depth: depth}}
+			lines[line] = g
+			*parent = append(*parent, g)
+		}
+		return g
+	}
+	for _, v := range a.allVersionsSorted() {
+		major, minor := releaseLines(v.Version)
+		mg := add(&majors, major, 0)
+		mg.docs = append(mg.docs, v)
+		if minor == major {
+			continue
+		}
+		ng := add(&mg.children, minor, 1)
+		ng.docs = append(ng.docs, v)
+	}
+
+	var rows []versionRow
+	var emit func(g *group)
+	emit = func(g *group) {
+		g.row.count = len(g.docs)
+		g.row.doc = latestRelease(g.docs)
+		rows = append(rows, g.row)
+		if !a.expanded(g.row.line) {
+			return
+		}
+		if len(g.children) == 0 {
+			for _, d := range g.docs {
+				rows = append(rows, versionRow{depth: 2, doc: d})
+			}
+			return
+		}
+		for _, c := range g.children {
+			emit(c)
+		}
+	}
+	for _, g := range majors {
+		emit(g)
+	}
+	return rows
+}
+
+// latestRelease is the newest stable version in docs, or the newest one if
+// all are pre-releases. docs are sorted newest first.
+func latestRelease(docs []api.Doc) api.Doc {
+	for _, d := range docs {
+		if !d.IsPreRelease() {
+			return d
+		}
+	}
+	return docs[0]
+}
+
+func (a *App) selectedRowKey() string {
+	rows := a.versionRows()
+	if a.versionCursor < len(rows) {
+		return rows[a.versionCursor].key()
+	}
+	return ""
+}
+
+func (a *App) cursorOnRow(key string) {
+	rows := a.versionRows()
+	for i, r := range rows {
+		if r.key() == key {
+			a.versionCursor = i
+			return
+		}
+	}
+	if a.versionCursor >= len(rows) {
+		a.versionCursor = max(len(rows)-1, 0)
+	}
 }
 
 func (a *App) viewVersions() string {
@@ -86,58 +437,92 @@ func (a *App) viewVersions() string {
 		return b.String()
 	}
 
-	allVersions := a.allVersionsSorted()
-	if len(allVersions) == 0 {
+	resolved, _ := a.resolvedVersion()
+	// Title(3) and help(2)
+	reservedHeight := 5
+	switch {
+	case a.rangeInput.Focused():
+		b.WriteString("  " + a.theme.Normal.Render(a.locale.T("versions.range.label")) + a.rangeInput.View() + "\n")
+		if a.rangeErr != "" {
+			b.WriteString("  " + a.theme.Error.Render(a.rangeErr) + "\n")
+			reservedHeight++
+		}
+		b.WriteString("\n")
+		reservedHeight += 2
+	case a.versionRange != nil && resolved != "":
+		b.WriteString("  " + a.theme.Success.Render(fmt.Sprintf(a.locale.T("versions.range"), a.versionRange, resolved)) + "\n\n")
+		reservedHeight += 2
+	case a.versionRange != nil:
+		b.WriteString("  " + a.theme.Error.Render(fmt.Sprintf(a.locale.T("versions.range.none"), a.versionRange)) + "\n\n")
+		reservedHeight += 2
+	}
+	if a.versionFilter.Focused() || a.versionFilter.Value() != "" {
+		b.WriteString("  " + a.theme.Normal.Render(a.locale.T("versions.filter.label")) + a.versionFilter.View() + "\n\n")
+		reservedHeight += 2
+	}
+
+	rows := a.versionRows()
+	if len(rows) == 0 {
+		if len(a.versions) > 0 {
+			b.WriteString("  " + a.theme.Dimmed.Render(a.locale.T("error.noresults")) + "\n")
+		}
+		b.WriteString("\n  " + a.theme.Help.Render(a.locale.T("versions.help")))
 		return b.String()
 	}
 
-	// Calculate available height
-	// Header: Title(3)
-	// Footer: Help(2)
-	// Sections: Headers(2 lines each)
-	reservedHeight := 6
 	availableHeight := a.height - reservedHeight
 	if availableHeight < 1 {
 		availableHeight = 1
 	}
 
-	// Simple scrolling: just show a window of versions around the cursor
+	// Show a window of rows around the cursor
 	startIdx := 0
-	if len(allVersions) > availableHeight {
+	if len(rows) > availableHeight {
 		startIdx = a.versionCursor - (availableHeight / 2)
 		if startIdx < 0 {
 			startIdx = 0
 		}
-		if startIdx+availableHeight > len(allVersions) {
-			startIdx = len(allVersions) - availableHeight
+		if startIdx+availableHeight > len(rows) {
+			startIdx = len(rows) - availableHeight
 		}
 	}
 	endIdx := startIdx + availableHeight
-	if endIdx > len(allVersions) {
-		endIdx = len(allVersions)
+	if endIdx > len(rows) {
+		endIdx = len(rows)
 	}
 
-	// Map version to their original section for rendering
 	for i := startIdx; i < endIdx; i++ {
-		v := allVersions[i]
-
-		// Show section header if it's the first item of its type in the viewport
-		// OR if it's the first item in the entire list
-		isFirstInStable := len(a.stableVersions) > 0 && i == 0
-		isFirstInPre := len(a.preVersions) > 0 && i == len(a.stableVersions)
+		row := rows[i]
+		indent := strings.Repeat("  ", row.depth)
 
-		if isFirstInStable {
-			b.WriteString("  " + a.theme.Subtitle.Render(a.locale.T("versions.stable")) + "\n")
-			b.WriteString("  " + a.theme.Separator.Render(strings.Repeat("─", 40)) + "\n")
-		} else if isFirstInPre {
-			b.WriteString("  " + a.theme.Subtitle.Render(a.locale.T("versions.prerelease")) + "\n")
-			b.WriteString("  " + a.theme.Separator.Render(strings.Repeat("─", 40)) + "\n")
+		var line string
+		if row.line != "" {
+			arrow := "▸"
+			if a.expanded(row.line) {
+				arrow = "▾"
+			}
+			line = fmt.Sprintf("  %s%s %-10s %-20s %s  %s", indent, arrow, row.line, row.doc.Version,
+				row.doc.Time().Format("2006-01-02"), fmt.Sprintf(a.locale.T("results.versionCount"), row.count))
+		} else {
+			v := row.doc
+			line = fmt.Sprintf("  %s%-20s %s", indent, v.Version, v.Time().Format("2006-01-02"))
+			if kind := version.Classify(v.Version); kind != version.Release {
+				line += "  " + kind.String()
+			}
+			if v.Version == resolved {
+				line += "  ← " + a.locale.T("versions.resolved")
+			}
 		}
 
-		line := fmt.Sprintf("  %-20s %s", v.Version, v.Time().Format("2006-01-02"))
-		if i == a.versionCursor {
+		switch {
+		case i == a.versionCursor:
 			b.WriteString(a.theme.Selected.Render(line) + "\n")
-		} else {
+		case row.line == "" && a.changed[row.doc.ID]:
+			// Updated by a background refresh
+			b.WriteString(a.theme.Success.Render("•") + a.theme.Normal.Render(line[1:]) + "\n")
+		case row.line != "":
+			b.WriteString(a.theme.Subtitle.Render(line) + "\n")
+		default:
 			b.WriteString(a.theme.Normal.Render(line) + "\n")
 		}
 	}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/maher/mvns/internal/api"
+)
+
+func rowKeys(rows []versionRow) []string {
+	out := make([]string, len(rows))
+	for i, r := range rows {
+		out[i] = r.key()
+	}
+	return out
+}
+
+func TestVersionsGroupedByLine(t *testing.T) {
+	app := newTestApp(t, &stubClient{})
+	app.searchInput.Blur()
+	app.screen = screenVersions
+	var docs []api.Doc
+	for _, v := range []string{"1.9", "2.0.0", "2.1.0-rc1", "1.10", "2.0.1", "2.1.0"} {
+		docs = append(docs, api.Doc{ID: "g:a:" + v, Version: v})
+	}
+	app.Update(versionResultMsg{id: app.selectedDoc.ID, resp: &api.SearchResponse{Response: api.ResponseBody{Docs: docs}}})
+
+	want := []string{"line:2", "line:2.1", "g:a:2.1.0", "g:a:2.1.0-rc1", "line:2.0", "line:1"}
+	if got := rowKeys(app.versionRows()); !slices.Equal(got, want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+	if r := app.versionRows()[0]; r.doc.Version != "2.1.0" || r.count != 4 {
+		t.Errorf("line 2 = %s (%d), want latest 2.1.0 of 4", r.doc.Version, r.count)
+	}
+
+	// Expand 1, then collapse it again from one of its versions
+	app.versionCursor = 5
+	app.Update(tea.KeyMsg{Type: tea.KeyRight})
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := app.versionRows()[app.versionCursor].key(); got != "line:1.10" {
+		t.Fatalf("cursor on %s, want line:1.10", got)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if got := app.versionRows()[app.versionCursor].key(); got != "line:1" || app.expanded("1") {
+		t.Errorf("left should collapse line 1, cursor on %s", got)
+	}
+
+	// "/" filters instead of jumping to search, and opens matching lines
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if app.screen != screenVersions || !app.versionFilter.Focused() {
+		t.Fatal("/ should focus the version filter")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1.1")})
+	want = []string{"line:1", "line:1.10", "g:a:1.10"}
+	if got := rowKeys(app.versionRows()); !slices.Equal(got, want) {
+		t.Errorf("filtered rows = %v, want %v", got, want)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.versionFilter.Value() != "" || app.screen != screenVersions {
+		t.Error("esc should clear the filter and stay on the screen")
+	}
+}
+
+type rowsClient struct {
+	stubClient
+	rows []int
+}
+
+func (r *rowsClient) Versions(ctx context.Context, groupID, artifactID string, rows int, bypassCache bool) (*api.SearchResponse, error) {
+	r.rows = append(r.rows, rows)
+	return &api.SearchResponse{}, nil
+}
+
+func TestOnlyOpeningVersionsPagesAll(t *testing.T) {
+	client := &rowsClient{}
+	app := newTestApp(t, client)
+	app.searchInput.Blur()
+	app.results = []api.Doc{{ID: "g:a", GroupID: "g", ArtifactID: "a"}}
+
+	_, cmd := app.Update(prefetchMsg{cursor: 0})
+	cmd()
+	app.selectedDoc = app.results[0]
+	app.fetchVersions()()
+	if !slices.Equal(client.rows, []int{versionPageRows, 0}) {
+		t.Errorf("rows = %v, want a bounded prefetch and a full listing", client.rows)
+	}
+}
@@ -0,0 +1,259 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a version requirement as written in a build file:
+//
+//	[1.2,2.0)  (,1.0],[1.2,)  [1.5]  1.5     Maven ranges
+//	1.+  +  latest.release  latest.integration  Gradle dynamic versions
+//	^2.3  ~2.3  >=1.2 <2                  semver-style
+//
+// Each picks what its build tool would: Maven ranges and Gradle dynamic
+// versions admit pre-releases, semver-style ones do not.
+type Constraint struct {
+	raw string
+	// intervals are alternatives; a version in any of them is allowed.
+	intervals []interval
+	// prefix is the string a Gradle "1.+" version must start with.
+	prefix     string
+	any        bool
+	stable     bool
+	noSnapshot bool
+}
+
+type interval struct {
+	lo, hi       *Version
+	loInc, hiInc bool
+}
+
+func (iv interval) contains(v Version) bool {
+	if iv.lo != nil {
+		c := v.Compare(*iv.lo)
+		if c < 0 || (c == 0 && !iv.loInc) {
+			return false
+		}
+	}
+	if iv.hi != nil {
+		c := v.Compare(*iv.hi)
+		if c > 0 || (c == 0 && !iv.hiInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseConstraint parses s, reporting malformed ranges.
+func ParseConstraint(s string) (Constraint, error) {
+	s = strings.TrimSpace(s)
+	c := Constraint{raw: s}
+	switch {
+	case s == "":
+		return c, fmt.Errorf("empty version constraint")
+	case s == "+" || s == "latest.integration":
+		c.any = true
+	case s == "latest.release" || s == "latest.milestone":
+		// Maven repositories only know snapshots from releases
+		c.any, c.noSnapshot = true, true
+	case strings.HasSuffix(s, "+"):
+		c.prefix = strings.TrimSuffix(s, "+")
+	case s[0] == '[' || s[0] == '(':
+		ivs, err := parseRanges(s)
+		if err != nil {
+			return c, err
+		}
+		c.intervals = ivs
+	case s[0] == '^' || s[0] == '~':
+		iv, err := parseSemver(s)
+		if err != nil {
+			return c, err
+		}
+		c.intervals, c.stable = []interval{iv}, true
+	case strings.ContainsAny(s[:1], "<>="):
+		iv, err := parseComparisons(s)
+		if err != nil {
+			return c, err
+		}
+		c.intervals, c.stable = []interval{iv}, true
+	default:
+		// A bare version asks for exactly that version
+		v := Parse(s)
+		c.intervals = []interval{{lo: &v, hi: &v, loInc: true, hiInc: true}}
+	}
+	return c, nil
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Allows reports whether v satisfies the constraint.
+func (c Constraint) Allows(v string) bool {
+	parsed := Parse(v)
+	kind := parsed.Kind()
+	if c.noSnapshot && kind == Snapshot {
+		return false
+	}
+	if c.stable && kind.PreRelease() {
+		return false
+	}
+	switch {
+	case c.any:
+		return true
+	case c.prefix != "":
+		return strings.HasPrefix(v, c.prefix)
+	}
+	for _, iv := range c.intervals {
+		if iv.contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve picks the highest of versions the constraint allows.
+func (c Constraint) Resolve(versions []string) (string, bool) {
+	var (
+		best   Version
+		picked string
+		ok     bool
+	)
+	for _, v := range versions {
+		if !c.Allows(v) {
+			continue
+		}
+		parsed := Parse(v)
+		if !ok || parsed.Compare(best) > 0 {
+			best, picked, ok = parsed, v, true
+		}
+	}
+	return picked, ok
+}
+
+// parseRanges reads one or more comma separated Maven ranges.
+func parseRanges(s string) ([]interval, error) {
+	var out []interval
+	rest := s
+	for rest != "" {
+		if rest[0] != '[' && rest[0] != '(' {
+			return nil, fmt.Errorf("invalid range %q: expected [ or ( at %q", s, rest)
+		}
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid range %q: missing ] or )", s)
+		}
+		iv, err := parseRange(rest[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q: %w", s, err)
+		}
+		out = append(out, iv)
+		rest = strings.TrimSpace(rest[end+1:])
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+			if rest == "" {
+				return nil, fmt.Errorf("invalid range %q: trailing comma", s)
+			}
+		} else if rest != "" {
+			return nil, fmt.Errorf("invalid range %q: expected , before %q", s, rest)
+		}
+	}
+	return out, nil
+}
+
+// parseRange reads "[1.0,2.0)", "(,1.0]" or "[1.0]".
+func parseRange(r string) (interval, error) {
+	iv := interval{loInc: r[0] == '[', hiInc: r[len(r)-1] == ']'}
+	body := r[1 : len(r)-1]
+	parts := strings.Split(body, ",")
+	switch len(parts) {
+	case 1:
+		if !iv.loInc || !iv.hiInc || strings.TrimSpace(body) == "" {
+			return iv, fmt.Errorf("a single version must be written [1.0]")
+		}
+		v := Parse(body)
+		iv.lo, iv.hi = &v, &v
+	case 2:
+		if lo := strings.TrimSpace(parts[0]); lo != "" {
+			v := Parse(lo)
+			iv.lo = &v
+		}
+		if hi := strings.TrimSpace(parts[1]); hi != "" {
+			v := Parse(hi)
+			iv.hi = &v
+		}
+		if iv.lo != nil && iv.hi != nil && iv.lo.Compare(*iv.hi) > 0 {
+			return iv, fmt.Errorf("lower bound %s is above upper bound %s", iv.lo, iv.hi)
+		}
+	default:
+		return iv, fmt.Errorf("too many versions in %s", r)
+	}
+	return iv, nil
+}
+
+// parseSemver reads "^2.3", up to the next major version (or minor for
+// 0.x), and "~2.3", up to the next minor version.
+func parseSemver(s string) (interval, error) {
+	base := strings.TrimSpace(s[1:])
+	parts := strings.Split(base, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return interval{}, fmt.Errorf("invalid version %q in %s", base, s)
+		}
+		nums[i] = n
+	}
+
+	bump := 0
+	if s[0] == '^' {
+		for bump < len(nums)-1 && nums[bump] == 0 {
+			bump++
+		}
+	} else if len(nums) > 1 {
+		bump = 1
+	}
+	upper := make([]string, bump+1)
+	for i := 0; i < bump; i++ {
+		upper[i] = strconv.Itoa(nums[i])
+	}
+	upper[bump] = strconv.Itoa(nums[bump] + 1)
+
+	lo, hi := Parse(base), Parse(strings.Join(upper, "."))
+	return interval{lo: &lo, hi: &hi, loInc: true}, nil
+}
+
+// parseComparisons reads space or comma separated ">=1.2 <2" and
+// intersects them.
+func parseComparisons(s string) (interval, error) {
+	var iv interval
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	for _, f := range fields {
+		op := strings.TrimRight(f[:min(2, len(f))], "0123456789.")
+		if op == "" || strings.Trim(op, "<>=") != "" {
+			return iv, fmt.Errorf("invalid comparison %q in %s", f, s)
+		}
+		arg := strings.TrimSpace(f[len(op):])
+		if arg == "" {
+			return iv, fmt.Errorf("missing version after %s in %s", op, s)
+		}
+		v := Parse(arg)
+		switch op {
+		case ">", ">=":
+			if iv.lo == nil || v.Compare(*iv.lo) >= 0 {
+				iv.lo, iv.loInc = &v, op == ">="
+			}
+		case "<", "<=":
+			if iv.hi == nil || v.Compare(*iv.hi) <= 0 {
+				iv.hi, iv.hiInc = &v, op == "<="
+			}
+		case "=":
+			iv.lo, iv.hi, iv.loInc, iv.hiInc = &v, &v, true, true
+		default:
+			return iv, fmt.Errorf("invalid comparison %q in %s", f, s)
+		}
+	}
+	return iv, nil
+}
@@ -0,0 +1,67 @@
+package version
+
+import "testing"
+
+func TestConstraintResolve(t *testing.T) {
+	versions := []string{
+		"1.0", "1.1", "1.2", "1.9.4", "1.10", "2.0-beta1", "2.0", "2.1-SNAPSHOT", "2.3", "2.4.1", "3.0-rc1", "3.0",
+	}
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"[1.2,2.0)", "2.0-beta1"},
+		{"[1.0,1.2]", "1.2"},
+		{"(,1.1]", "1.1"},
+		{"[1.0,1.1),[2.3,2.4)", "2.3"},
+		{"[1.9.4]", "1.9.4"},
+		{"1.1", "1.1"},
+		{"1.+", "1.10"},
+		{"1.9+", "1.9.4"},
+		{"1.1+", "1.10"},
+		{"+", "3.0"},
+		{"latest.release", "3.0"},
+		{"latest.integration", "3.0"},
+		{"^2.3", "2.4.1"},
+		{"~2.3", "2.3"},
+		{"^1", "1.10"},
+		{">=1.2 <2", "1.10"},
+		{">2.0, <=3.0", "3.0"},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) failed: %v", tt.constraint, err)
+			continue
+		}
+		if got, _ := c.Resolve(versions); got != tt.want {
+			t.Errorf("%s resolves to %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+
+	c, _ := ParseConstraint("[4.0,)")
+	if v, ok := c.Resolve(versions); ok {
+		t.Errorf("[4.0,) resolved to %s, want nothing", v)
+	}
+}
+
+func TestConstraintSnapshots(t *testing.T) {
+	versions := []string{"1.0", "1.1-SNAPSHOT"}
+	for constraint, want := range map[string]string{
+		"latest.integration": "1.1-SNAPSHOT",
+		"latest.release":     "1.0",
+	} {
+		c, _ := ParseConstraint(constraint)
+		if got, _ := c.Resolve(versions); got != want {
+			t.Errorf("%s resolves to %q, want %q", constraint, got, want)
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{"", "[1.0,2.0", "[2.0,1.0]", "(1.0)", "[1,2,3]", "[1.0,2.0)x", "^a.b", ">=", "[1.0,2.0),"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) should fail", s)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kind classifies a version by its qualifier.
+type Kind int
+
+const (
+	Release Kind = iota
+	Alpha
+	Beta
+	Milestone
+	RC
+	Snapshot
+	// Preview covers early-access builds: preview, ea, dev and nightly.
+	Preview
+	// ServicePack is a release after the release, such as 1.0-sp1.
+	ServicePack
+	// Other is an unknown qualifier such as -jre or -android, which names a
+	// variant of a release rather than a pre-release.
+	Other
+)
+
+var kindNames = []string{"release", "alpha", "beta", "milestone", "rc", "snapshot", "preview", "sp", "other"}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// PreRelease reports whether k comes before the release it leads up to.
+func (k Kind) PreRelease() bool {
+	switch k {
+	case Alpha, Beta, Milestone, RC, Snapshot, Preview:
+		return true
+	}
+	return false
+}
+
+var previews = map[string]bool{"preview": true, "ea": true, "dev": true, "nightly": true, "pr": true}
+
+// Classify returns the kind of the first qualifier in v that is not a
+// plain release marker like Final or GA.
+func Classify(v string) Kind {
+	return Parse(v).Kind()
+}
+
+// timestampedSnapshot matches the deployed form of a snapshot,
+// 1.0-20240101.123456-1.
+var timestampedSnapshot = regexp.MustCompile(`-\d{8}\.\d{6}-\d+$`)
+
+func (v Version) Kind() Kind {
+	if timestampedSnapshot.MatchString(v.raw) {
+		return Snapshot
+	}
+	q, ok := firstQualifier(v.items)
+	if !ok {
+		return Release
+	}
+	// JDK-style build numbers follow a +, as in 21-ea+30 or 21+35
+	name, _, _ := strings.Cut(string(q), "+")
+	switch name {
+	case "":
+		return Release
+	case "alpha":
+		return Alpha
+	case "beta":
+		return Beta
+	case "milestone":
+		return Milestone
+	case "rc":
+		return RC
+	case "snapshot":
+		return Snapshot
+	case "sp":
+		return ServicePack
+	}
+	if previews[name] {
+		return Preview
+	}
+	return Other
+}
+
+// IsPreRelease reports whether v is an alpha, beta, milestone, release
+// candidate, snapshot or preview.
+func IsPreRelease(v string) bool {
+	return Classify(v).PreRelease()
+}
+
+func firstQualifier(l list) (qualifier, bool) {
+	for _, it := range l {
+		switch it := it.(type) {
+		case qualifier:
+			if !it.isNull() {
+				return it, true
+			}
+		case list:
+			if q, ok := firstQualifier(it); ok {
+				return q, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,286 @@
+// Package version orders and classifies Maven versions the way Maven's
+// ComparableVersion does, so 1.10 sorts after 1.9, 1.0-alpha1 before 1.0
+// and 1.0.Final equal to 1.0.
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version string.
+type Version struct {
+	raw   string
+	items list
+}
+
+func Parse(s string) Version {
+	return Version{raw: s, items: parse(strings.ToLower(strings.TrimSpace(s)))}
+}
+
+func (v Version) String() string {
+	return v.raw
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o.
+func (v Version) Compare(o Version) int {
+	return v.items.compare(o.items)
+}
+
+// Compare compares two version strings, see Version.Compare.
+func Compare(a, b string) int {
+	return Parse(a).Compare(Parse(b))
+}
+
+// item is one component of a version: a number, a qualifier or a nested
+// list started by "-" or a switch between digits and letters.
+type item interface {
+	// compare orders the item against o, which is nil when the other
+	// version has no item at this position.
+	compare(o item) int
+	isNull() bool
+}
+
+// number keeps the digits without leading zeros so any length compares.
+type number string
+
+func (n number) isNull() bool { return n == "" }
+
+func (n number) compare(o item) int {
+	switch o := o.(type) {
+	case nil:
+		if n.isNull() {
+			return 0
+		}
+		return 1
+	case number:
+		if len(n) != len(o) {
+			return sign(len(n) - len(o))
+		}
+		return strings.Compare(string(n), string(o))
+	}
+	// 1.1 > 1-sp and 1.1 > 1-1
+	return 1
+}
+
+// qualifiers are the well-known qualifiers, oldest first; "" is a release.
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var aliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// releaseIndex is the comparable form of a release, "".
+var releaseIndex = strconv.Itoa(indexOf(qualifiers, ""))
+
+type qualifier string
+
+func newQualifier(s string, followedByDigit bool) qualifier {
+	if followedByDigit && len(s) == 1 {
+		// 1.0a1 is 1.0-alpha-1
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := aliases[s]; ok {
+		s = alias
+	}
+	return qualifier(s)
+}
+
+// comparable maps known qualifiers to their rank and sorts unknown ones
+// after all of them, lexically.
+func (q qualifier) comparable() string {
+	if i := indexOf(qualifiers, string(q)); i >= 0 {
+		return strconv.Itoa(i)
+	}
+	return strconv.Itoa(len(qualifiers)) + "-" + string(q)
+}
+
+func (q qualifier) isNull() bool { return q.comparable() == releaseIndex }
+
+func (q qualifier) compare(o item) int {
+	switch o := o.(type) {
+	case nil:
+		// 1-rc < 1, 1-sp > 1
+		return strings.Compare(q.comparable(), releaseIndex)
+	case qualifier:
+		return strings.Compare(q.comparable(), o.comparable())
+	}
+	return -1
+}
+
+type list []item
+
+func (l list) isNull() bool { return len(l) == 0 }
+
+func (l list) compare(o item) int {
+	switch o := o.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compare(nil)
+	case number:
+		return -1
+	case qualifier:
+		return 1
+	case list:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var a, b item
+			if i < len(l) {
+				a = l[i]
+			}
+			if i < len(o) {
+				b = o[i]
+			}
+			var c int
+			if a == nil {
+				if b != nil {
+					c = -b.compare(nil)
+				}
+			} else {
+				c = a.compare(b)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// normalize drops trailing null items, so 1.0.0 equals 1.
+func (l list) normalize() list {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].isNull() {
+			l = append(l[:i], l[i+1:]...)
+		} else if _, ok := l[i].(list); !ok {
+			break
+		}
+	}
+	return l
+}
+
+// parse splits s into items: "." separates items, "-" and every switch
+// between digits and letters start a nested list. s is lower case.
+func parse(s string) list {
+	// Nested lists are built through pointers and normalized innermost first
+	root := &[]item{}
+	cur := root
+	push := func() {
+		next := &[]item{}
+		*cur = append(*cur, listRef{next})
+		cur = next
+	}
+
+	start, digit := 0, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		// 1.0.CR1 is 1.0-CR1, as in Maven 3.9
+		if c == '.' && i+1 < len(s) && s[i+1] >= 'a' && s[i+1] <= 'z' {
+			c = '-'
+		}
+		switch {
+		case c == '.':
+			if i == start {
+				*cur = append(*cur, number(""))
+			} else {
+				*cur = append(*cur, newItem(s[start:i], digit, false))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				*cur = append(*cur, number(""))
+			} else {
+				*cur = append(*cur, newItem(s[start:i], digit, false))
+			}
+			start = i + 1
+			push()
+		case c >= '0' && c <= '9':
+			if !digit && i > start {
+				*cur = append(*cur, newItem(s[start:i], false, true))
+				start = i
+				push()
+			}
+			digit = true
+		default:
+			if digit && i > start {
+				*cur = append(*cur, newItem(s[start:i], true, false))
+				start = i
+				push()
+			}
+			digit = false
+		}
+	}
+	if len(s) > start {
+		*cur = append(*cur, newItem(s[start:], digit, false))
+	}
+	return resolve(*root)
+}
+
+// listRef is a nested list while parsing, before it is resolved.
+type listRef struct{ l *[]item }
+
+func (r listRef) compare(item) int { return 0 }
+func (r listRef) isNull() bool     { return len(*r.l) == 0 }
+
+// resolve replaces parse references by their lists, normalizing each.
+func resolve(items []item) list {
+	out := make(list, len(items))
+	for i, it := range items {
+		if r, ok := it.(listRef); ok {
+			out[i] = resolve(*r.l)
+		} else {
+			out[i] = it
+		}
+	}
+	return out.normalize()
+}
+
+func newItem(s string, digit, followedByDigit bool) item {
+	if digit {
+		return number(strings.TrimLeft(s, "0"))
+	}
+	return newQualifier(s, followedByDigit)
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Line returns the release line of v: its major version at depth 1, such
+// as "2", and major.minor at depth 2, such as "2.15". Versions that do not
+// start with a number belong to no line.
+func Line(v string, depth int) (string, bool) {
+	end := 0
+	for end < len(v) && (v[end] == '.' || v[end] >= '0' && v[end] <= '9') {
+		end++
+	}
+	parts := strings.FieldsFunc(v[:end], func(r rune) bool { return r == '.' })
+	if len(parts) == 0 {
+		return "", false
+	}
+	for len(parts) < depth {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts[:depth], "."), true
+}
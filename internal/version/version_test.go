@@ -0,0 +1,103 @@
+package version
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCompareOrder(t *testing.T) {
+	// Each version is older than the next, from Maven's own test suite
+	ordered := []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11",
+		"1-rc", "1-cr2", "1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc",
+		"1-def", "1-pom-1", "1-1-snapshot", "1-1", "1-2", "1-123", "1.1", "1.9", "1.10",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		if c := Compare(ordered[i], ordered[i+1]); c != -1 {
+			t.Errorf("Compare(%q, %q) = %d, want -1", ordered[i], ordered[i+1], c)
+		}
+		if c := Compare(ordered[i+1], ordered[i]); c != 1 {
+			t.Errorf("Compare(%q, %q) = %d, want 1", ordered[i+1], ordered[i], c)
+		}
+	}
+}
+
+func TestCompareEqual(t *testing.T) {
+	equal := [][2]string{
+		{"1", "1.0.0"},
+		{"1.0.Final", "1.0"},
+		{"1-ga", "1"},
+		{"1.0.CR1", "1.0-rc1"},
+		{"1a1", "1-alpha-1"},
+		{"1.0-RELEASE", "1.0"},
+		{"1.01", "1.1"},
+	}
+	for _, e := range equal {
+		if c := Compare(e[0], e[1]); c != 0 {
+			t.Errorf("Compare(%q, %q) = %d, want 0", e[0], e[1], c)
+		}
+	}
+
+	vs := []string{"2.0", "10.0", "1.10", "1.9", "2.0-rc1"}
+	sort.Slice(vs, func(i, j int) bool { return Compare(vs[i], vs[j]) < 0 })
+	if got := vs; got[0] != "1.9" || got[2] != "2.0-rc1" || got[4] != "10.0" {
+		t.Errorf("sorted = %v", got)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		v    string
+		want Kind
+	}{
+		{"1.0", Release},
+		{"5.4.0.Final", Release},
+		{"1.0.GA", Release},
+		{"1.0-alpha-1", Alpha},
+		{"2.0b3", Beta},
+		{"2.0.Beta2", Beta},
+		{"6.0.0-M1", Milestone},
+		{"1.0.CR1", RC},
+		{"1.0-rc-2", RC},
+		{"1.0-SNAPSHOT", Snapshot},
+		{"21-ea", Preview},
+		{"21-ea+30", Preview},
+		{"21+35", Release},
+		{"1.0-20240101.123456-1", Snapshot},
+		{"1.0-preview", Preview},
+		{"1.0-sp1", ServicePack},
+		{"1.0-migration", Other},
+		{"33.0.0-jre", Other},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.v); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.v, got, tt.want)
+		}
+	}
+	if IsPreRelease("1.0-migration") || !IsPreRelease("2.0.Beta2") {
+		t.Error("IsPreRelease misclassifies qualifiers")
+	}
+}
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		v           string
+		major, line string
+	}{
+		{"2.15.4", "2", "2.15"},
+		{"4.1.100.Final", "4", "4.1"},
+		{"33.0.0-jre", "33", "33.0"},
+		{"5", "5", "5.0"},
+		{"2.0-rc1", "2", "2.0"},
+	}
+	for _, tt := range tests {
+		major, _ := Line(tt.v, 1)
+		line, _ := Line(tt.v, 2)
+		if major != tt.major || line != tt.line {
+			t.Errorf("Line(%q) = %q, %q, want %q, %q", tt.v, major, line, tt.major, tt.line)
+		}
+	}
+	if _, ok := Line("r09", 1); ok {
+		t.Error("r09 should belong to no line")
+	}
+}